@@ -2,55 +2,251 @@ package app
 
 import (
 	"apigateway/core/config"
+	"apigateway/core/handler"
+	"apigateway/core/middleware"
 	"apigateway/core/routes"
 	"apigateway/core/usecases"
 	"apigateway/infra/clients"
-	"apigateway/pkg/logger"
+	"apigateway/pkg/metrics"
+	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"pkg/conf"
+	"pkg/logger"
+	"pkg/tlsreload"
+	"pkg/tracing"
+	"syscall"
+	"time"
 
 	_ "apigateway/docs"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.uber.org/zap"
 )
 
+// serviceName names the gateway in traces.
+const serviceName = "apigateway"
+
 // @title           API Gateway Swagger
 // @version         1.0
 // @description     API Gateway for Movies Service
 // @host            localhost:8080
 // @BasePath        /
 // @schemes         http
-func SetupRouter() (*gin.Engine, *zap.Logger, error) {
-	cfg := config.Load()
-	log := logger.New(cfg.Env)
-
-	grpcClient, err := clients.NewGrpcClient(&cfg)
-
-	if err != nil {
-		return nil, log, err
-	}
-
+func SetupRouter(
+	cfg *config.Config,
+	log *zap.Logger,
+	grpcClient *clients.MoviesGRPCClient,
+	readiness *handler.Readiness,
+) *gin.Engine {
 	if cfg.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
-	moviesUsecases := usecases.NewMoviesUseCases(grpcClient, log)
-	router := gin.Default()
+	moviesUsecases := usecases.NewMoviesUseCases(grpcClient, log.Named("movies"))
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(otelgin.Middleware(serviceName))
+	router.Use(metrics.GinMiddleware())
+	// One access line per request: sampled, so load spikes can't flood
+	// the output. Warnings and errors always get through.
+	router.Use(middleware.AccessLog(logger.Sample(log.Named("access"), cfg.LogSamplingInitial, cfg.LogSamplingThereafter)))
+	router.Use(middleware.Recovery(log))
+
+	if cfg.HTTPTLSCertFile != "" && cfg.HSTSMaxAge > 0 {
+		router.Use(middleware.HSTS(cfg.HSTSMaxAge))
+	}
+
 	router.SetTrustedProxies(nil)
-	routes.Register(router, moviesUsecases, log)
+	healthHandler := handler.NewHealthHandler(readiness, cfg.HealthCheckTimeout, log.Named("health"), grpcClient)
+	routes.Register(router, moviesUsecases, log, healthHandler)
 
-	return router, log, nil
+	return router
 }
 
 func Run() error {
-	router, log, err := SetupRouter()
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, conf.ErrPrintedConfig) {
+		return nil
+	}
 	if err != nil {
 		return err
 	}
 
-	cfg := config.Load()
-	listenPort := fmt.Sprintf(":%s", cfg.ListenPort)
+	log, levels := logger.New(cfg.Env, logger.Options{
+		Level:          cfg.LogLevel,
+		Levels:         cfg.LogLevels,
+		File:           cfg.LogFile,
+		FileMaxSizeMB:  cfg.LogFileMaxSizeMB,
+		FileMaxBackups: cfg.LogFileMaxBackups,
+		FileMaxAgeDays: cfg.LogFileMaxAgeDays,
+	})
+	defer logger.Sync(log)
+
+	shutdownTracing, err := tracing.Init(serviceName, cfg.TracingExporter, cfg.OtlpEndpoint)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	grpcClient, err := clients.NewGrpcClient(&cfg, log.Named("grpc"))
+	if err != nil {
+		return err
+	}
+
+	readiness := handler.NewReadiness()
+	router := SetupRouter(&cfg, log, grpcClient, readiness)
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	servers, err := buildServers(watchCtx, &cfg, router, log)
+	if err != nil {
+		return err
+	}
+
+	if cfg.AdminToken == "" {
+		log.Info("ADMIN_TOKEN is not set, /config and /log/level are disabled")
+	}
+
+	metricsServer := metrics.NewServer(cfg.MetricsPort, AdminHandlers(&cfg, levels))
+	go metrics.Serve(metricsServer, log)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, len(servers))
+	for _, server := range servers {
+		go serve(server, log, serverErr)
+	}
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Info("Shutdown signal received, draining connections",
+		zap.Duration("drain_delay", cfg.DrainDelay),
+		zap.Duration("timeout", cfg.ShutdownTimeout),
+	)
+	readiness.SetReady(false)
+	time.Sleep(cfg.DrainDelay)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error("HTTP server did not drain in time", zap.String("address", server.Addr), zap.Error(err))
+		}
+	}
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("Metrics server did not shut down cleanly", zap.Error(err))
+	}
+
+	if err := grpcClient.Close(); err != nil {
+		log.Error("Failed to close gRPC client", zap.Error(err))
+	}
+
+	log.Info("API Gateway stopped")
+	return nil
+}
+
+// buildServers returns the plain HTTP server alone, or, when a certificate
+// is configured, an HTTPS server speaking HTTP/1.1 and HTTP/2 plus the
+// plain one redirecting to it. The certificate is reloaded when the files
+// change on disk or on SIGHUP.
+func buildServers(ctx context.Context, cfg *config.Config, router http.Handler, log *zap.Logger) ([]*http.Server, error) {
+	plain := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.ListenPort),
+		Handler: router,
+	}
+
+	if cfg.HTTPTLSCertFile == "" {
+		return []*http.Server{plain}, nil
+	}
 
-	log.Info("API Gateway running", zap.String("address", listenPort))
-	return router.Run(listenPort)
+	reloader, err := tlsreload.New(cfg.HTTPTLSCertFile, cfg.HTTPTLSKeyFile, "")
+	if err != nil {
+		return nil, err
+	}
+
+	go reloader.Watch(ctx, cfg.TLSReloadInterval, log.Named("tls"))
+	go reloadOnHangup(ctx, reloader, log)
+
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+
+	secure := &http.Server{
+		Addr:      fmt.Sprintf(":%s", cfg.HTTPSPort),
+		Handler:   router,
+		Protocols: protocols,
+		TLSConfig: &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		},
+	}
+
+	if cfg.HTTPRedirect {
+		plain.Handler = middleware.RedirectToHTTPS(cfg.HTTPSPort, router)
+	}
+
+	return []*http.Server{secure, plain}, nil
+}
+
+func serve(server *http.Server, log *zap.Logger, serverErr chan<- error) {
+	var err error
+	if server.TLSConfig != nil {
+		log.Info("API Gateway running with TLS", zap.String("address", server.Addr))
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		log.Info("API Gateway running", zap.String("address", server.Addr))
+		err = server.ListenAndServe()
+	}
+
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		serverErr <- err
+	}
+}
+
+func reloadOnHangup(ctx context.Context, reloader *tlsreload.Reloader, log *zap.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+		}
+
+		if err := reloader.Reload(); err != nil {
+			log.Error("Failed to reload HTTPS certificate on SIGHUP", zap.Error(err))
+			continue
+		}
+
+		log.Info("HTTPS certificate reloaded on SIGHUP")
+	}
+}
+
+// AdminHandlers are the extra routes of the metrics port. /config is
+// redacted but still maps the deployment, so like /log/level it is only
+// served behind ADMIN_TOKEN, and neither exists without one.
+func AdminHandlers(cfg *config.Config, levels *logger.Levels) map[string]http.Handler {
+	admin := map[string]http.Handler{}
+	if cfg.AdminToken != "" {
+		admin["/config"] = metrics.RequireToken(cfg.AdminToken, cfg.Settings.Handler())
+		admin["/log/level"] = metrics.RequireToken(cfg.AdminToken, levels.Handler())
+	}
+	return admin
 }
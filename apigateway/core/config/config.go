@@ -1,30 +1,115 @@
 package config
 
 import (
-	"os"
+	"errors"
+	"fmt"
+	"pkg/conf"
+	"pkg/logger"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// Config is loaded by conf from, in increasing precedence, the defaults
+// below, the file named by CONFIG_FILE or -config, the environment (a
+// local .env included) and command-line flags. See pkg/conf for the tags.
 type Config struct {
-	Env               string
-	ListenPort        string
-	GrpcServerAddress string
+	Env                string        `env:"ENV" default:"development" validate:"required"`
+	ListenPort         string        `env:"LISTEN_PORT" default:"8080" validate:"required,port"`
+	GrpcServerAddress  string        `env:"GRPC_SERVER" default:"localhost:9090" validate:"required"`
+	MetricsPort        string        `env:"METRICS_PORT" default:"9100" validate:"required,port"`
+	TracingExporter    string        `env:"TRACING_EXPORTER" default:"none" validate:"oneof=none|stdout|otlp"`
+	OtlpEndpoint       string        `env:"OTEL_EXPORTER_OTLP_ENDPOINT" default:"localhost:4317"`
+	ShutdownTimeout    time.Duration `env:"SHUTDOWN_TIMEOUT" default:"15s"`
+	DrainDelay         time.Duration `env:"DRAIN_DELAY" default:"5s"`
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	GrpcReadTimeout         time.Duration `env:"GRPC_READ_TIMEOUT" default:"3s"`
+	GrpcWriteTimeout        time.Duration `env:"GRPC_WRITE_TIMEOUT" default:"5s"`
+	GrpcRetryMaxAttempts    int           `env:"GRPC_RETRY_MAX_ATTEMPTS" default:"3" validate:"min=1,max=5"`
+	GrpcRetryInitialBackoff time.Duration `env:"GRPC_RETRY_INITIAL_BACKOFF" default:"100ms"`
+	GrpcRetryMaxBackoff     time.Duration `env:"GRPC_RETRY_MAX_BACKOFF" default:"1s"`
+
+	BreakerFailureThreshold int           `env:"BREAKER_FAILURE_THRESHOLD" default:"5" validate:"min=1"`
+	BreakerOpenTimeout      time.Duration `env:"BREAKER_OPEN_TIMEOUT" default:"10s"`
+	BreakerHalfOpenMaxCalls int           `env:"BREAKER_HALF_OPEN_MAX_CALLS" default:"1" validate:"min=1"`
+
+	GrpcResolveInterval        time.Duration `env:"GRPC_RESOLVE_INTERVAL" default:"30s"`
+	GrpcLbPolicy               string        `env:"GRPC_LB_POLICY" default:"round_robin" validate:"oneof=round_robin|least_request"`
+	OutlierConsecutiveFailures int           `env:"OUTLIER_CONSECUTIVE_FAILURES" default:"5" validate:"min=1"`
+	OutlierBaseEjectionTime    time.Duration `env:"OUTLIER_BASE_EJECTION_TIME" default:"30s"`
+	OutlierMaxEjectionPercent  int           `env:"OUTLIER_MAX_EJECTION_PERCENT" default:"50" validate:"min=0,max=100"`
+
+	GrpcTLSCertFile   string        `env:"GRPC_TLS_CERT_FILE"`
+	GrpcTLSKeyFile    string        `env:"GRPC_TLS_KEY_FILE"`
+	GrpcTLSCAFile     string        `env:"GRPC_TLS_CA_FILE"`
+	GrpcTLSServerName string        `env:"GRPC_TLS_SERVER_NAME" default:"movies"`
+	TLSReloadInterval time.Duration `env:"TLS_RELOAD_INTERVAL" default:"30s"`
+
+	HTTPSPort       string        `env:"HTTPS_PORT" default:"8443" validate:"port"`
+	HTTPTLSCertFile string        `env:"HTTP_TLS_CERT_FILE"`
+	HTTPTLSKeyFile  string        `env:"HTTP_TLS_KEY_FILE"`
+	HTTPRedirect    bool          `env:"HTTP_REDIRECT" default:"true"`
+	HSTSMaxAge      time.Duration `env:"HSTS_MAX_AGE" default:"8760h"`
+
+	// Logging. LOG_LEVEL defaults to debug in development and info in
+	// production; LOG_LEVELS sets named loggers apart ("grpc=debug").
+	// Both can be changed at runtime on the admin server's /log/level,
+	// which, like /config, is only mounted when ADMIN_TOKEN is set.
+	LogLevel              string `env:"LOG_LEVEL" validate:"oneof=debug|info|warn|error"`
+	LogLevels             string `env:"LOG_LEVELS"`
+	LogSamplingInitial    int    `env:"LOG_SAMPLING_INITIAL" default:"100" validate:"min=1"`
+	LogSamplingThereafter int    `env:"LOG_SAMPLING_THEREAFTER" default:"100" validate:"min=0"`
+	LogFile               string `env:"LOG_FILE"`
+	LogFileMaxSizeMB      int    `env:"LOG_FILE_MAX_SIZE_MB" default:"100" validate:"min=1"`
+	LogFileMaxBackups     int    `env:"LOG_FILE_MAX_BACKUPS" default:"5" validate:"min=0"`
+	LogFileMaxAgeDays     int    `env:"LOG_FILE_MAX_AGE_DAYS" default:"28" validate:"min=0"`
+	AdminToken            string `env:"ADMIN_TOKEN" secret:"true"`
+
+	// Settings is the effective configuration with secrets redacted, for
+	// -print-config and the admin /config endpoint.
+	Settings conf.Settings
 }
 
-func Load() Config {
+// Load reads the configuration, taking flags from args; pass nil from
+// tools and tests that parse their own flags.
+func Load(args []string) (Config, error) {
 	_ = godotenv.Load()
 
-	return Config{
-		Env:               getEnv("ENV", "development"),
-		ListenPort:        getEnv("LISTEN_PORT", "8080"),
-		GrpcServerAddress: getEnv("GRPC_SERVER", "localhost:9090"),
-	}
+	var cfg Config
+	settings, err := conf.Load(&cfg, conf.Options{Args: args})
+	cfg.Settings = settings
+	return cfg, err
 }
 
-func getEnv(key, fallback string) string {
-	if val, ok := os.LookupEnv(key); ok {
-		return val
+func (cfg *Config) Validate() error {
+	var problems []error
+
+	if (cfg.GrpcTLSCertFile == "") != (cfg.GrpcTLSKeyFile == "") {
+		problems = append(problems, errors.New("GRPC_TLS_CERT_FILE and GRPC_TLS_KEY_FILE must be set together"))
 	}
-	return fallback
+	if cfg.GrpcTLSCertFile != "" && cfg.GrpcTLSCAFile == "" {
+		problems = append(problems, errors.New("GRPC_TLS_CA_FILE is required with GRPC_TLS_CERT_FILE, mTLS must not fall back to the system roots"))
+	}
+	if (cfg.HTTPTLSCertFile == "") != (cfg.HTTPTLSKeyFile == "") {
+		problems = append(problems, errors.New("HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE must be set together"))
+	}
+	if cfg.GrpcRetryInitialBackoff > cfg.GrpcRetryMaxBackoff {
+		problems = append(problems, errors.New("GRPC_RETRY_INITIAL_BACKOFF must not exceed GRPC_RETRY_MAX_BACKOFF"))
+	}
+	if cfg.ShutdownTimeout <= 0 || cfg.HealthCheckTimeout <= 0 || cfg.GrpcReadTimeout <= 0 || cfg.GrpcWriteTimeout <= 0 || cfg.TLSReloadInterval <= 0 {
+		problems = append(problems, errors.New("SHUTDOWN_TIMEOUT, HEALTH_CHECK_TIMEOUT, GRPC_READ_TIMEOUT, GRPC_WRITE_TIMEOUT and TLS_RELOAD_INTERVAL must be positive"))
+	}
+	// The resolver ticks on GRPC_RESOLVE_INTERVAL, and a zero open or
+	// ejection time would let the breaker and the outlier detector retry
+	// a failing backend without any pause.
+	if cfg.GrpcResolveInterval <= 0 || cfg.BreakerOpenTimeout <= 0 || cfg.OutlierBaseEjectionTime <= 0 {
+		problems = append(problems, errors.New("GRPC_RESOLVE_INTERVAL, BREAKER_OPEN_TIMEOUT and OUTLIER_BASE_EJECTION_TIME must be positive"))
+	}
+
+	if _, err := logger.ParseLevels(cfg.LogLevels); err != nil {
+		problems = append(problems, fmt.Errorf("LOG_LEVELS: %w", err))
+	}
+
+	return errors.Join(problems...)
 }
@@ -1,8 +1,8 @@
 package domain
 
 import (
-	"apigateway/core/proto"
 	"apigateway/core/util"
+	"proto"
 )
 
 type Movie struct {
@@ -73,12 +73,18 @@ func IsResultsPerPageValid(pageSize int) error {
 }
 
 func IsValidMovie(movie *Movie) error {
+	var violations []util.FieldViolation
+
 	if movie.Title == "" {
-		return util.ErrTitleEmpty
+		violations = append(violations, util.FieldViolation{Field: "title", Description: util.ErrTitleEmpty.Error()})
 	}
 
 	if movie.Year == "" {
-		return util.ErrYearEmpty
+		violations = append(violations, util.FieldViolation{Field: "year", Description: util.ErrYearEmpty.Error()})
+	}
+
+	if len(violations) > 0 {
+		return util.NewValidationError(violations...)
 	}
 
 	return nil
@@ -1,13 +1,157 @@
 package handler
 
 import (
+	"context"
 	"net/http"
+	"pkg/logger"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
-func RegisterHealthRoute(rg *gin.RouterGroup) {
-	rg.GET("/health", func(context *gin.Context) {
-		context.JSON(http.StatusOK, gin.H{"status": "healthy"})
+const (
+	statusUp       = "up"
+	statusDown     = "down"
+	statusReady    = "ready"
+	statusNotReady = "not_ready"
+	statusAlive    = "alive"
+	statusDraining = "draining"
+
+	// Public reasons for a failed check; the error itself can name hosts
+	// and certificates, so it only goes to the logs.
+	reasonTimeout     = "timeout"
+	reasonUnavailable = "unavailable"
+)
+
+// Readiness is flipped to false as soon as a shutdown starts so load
+// balancers stop routing new requests before the server drains.
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func NewReadiness() *Readiness {
+	readiness := &Readiness{}
+	readiness.SetReady(true)
+	return readiness
+}
+
+func (readiness *Readiness) SetReady(ready bool) {
+	readiness.ready.Store(ready)
+}
+
+func (readiness *Readiness) IsReady() bool {
+	return readiness.ready.Load()
+}
+
+type HealthChecker interface {
+	Name() string
+	CheckHealth(ctx context.Context) error
+}
+
+type DependencyStatus struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type HealthHandler struct {
+	Readiness *Readiness
+	Timeout   time.Duration
+	Checkers  []HealthChecker
+	Logger    *zap.Logger
+}
+
+func NewHealthHandler(readiness *Readiness, timeout time.Duration, log *zap.Logger, checkers ...HealthChecker) *HealthHandler {
+	return &HealthHandler{
+		Readiness: readiness,
+		Timeout:   timeout,
+		Checkers:  checkers,
+		Logger:    log,
+	}
+}
+
+// Livez only tells whether the process can serve HTTP at all; it never
+// looks at dependencies so an outage downstream doesn't get us restarted.
+func (handler *HealthHandler) Livez(context *gin.Context) {
+	context.JSON(http.StatusOK, gin.H{"status": statusAlive})
+}
+
+func (handler *HealthHandler) Readyz(context *gin.Context) {
+	if !handler.Readiness.IsReady() {
+		context.JSON(http.StatusServiceUnavailable, gin.H{"status": statusDraining})
+		return
+	}
+
+	checks := handler.runChecks(context.Request.Context())
+
+	httpStatus, status := http.StatusOK, statusReady
+	for _, check := range checks {
+		if check.Status != statusUp {
+			httpStatus, status = http.StatusServiceUnavailable, statusNotReady
+			break
+		}
+	}
+
+	context.JSON(httpStatus, gin.H{
+		"status": status,
+		"checks": checks,
 	})
 }
+
+func (handler *HealthHandler) runChecks(ctx context.Context) map[string]DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, handler.Timeout)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var group sync.WaitGroup
+	checks := make(map[string]DependencyStatus, len(handler.Checkers))
+
+	for _, checker := range handler.Checkers {
+		group.Add(1)
+		go func(checker HealthChecker) {
+			defer group.Done()
+
+			start := time.Now()
+			err := checker.CheckHealth(ctx)
+			result := DependencyStatus{
+				Status:  statusUp,
+				Latency: time.Since(start).String(),
+			}
+
+			if err != nil {
+				result.Status = statusDown
+				result.Error = reasonUnavailable
+				if ctx.Err() != nil {
+					result.Error = reasonTimeout
+				}
+				logger.WithContext(ctx, handler.Logger).Warn("Readiness check failed",
+					zap.String("dependency", checker.Name()), zap.Error(err))
+			}
+
+			mutex.Lock()
+			checks[checker.Name()] = result
+			mutex.Unlock()
+		}(checker)
+	}
+
+	group.Wait()
+	return checks
+}
+
+// LegacyHealth answers the old /v1/health URL with the /readyz result so
+// probes that still use it keep working for one more release.
+//
+// Deprecated: use /readyz.
+func (handler *HealthHandler) LegacyHealth(context *gin.Context) {
+	context.Header("Deprecation", "true")
+	context.Header("Link", `</readyz>; rel="successor-version"`)
+	handler.Readyz(context)
+}
+
+func RegisterHealthRoutes(router gin.IRoutes, healthHandler *HealthHandler) {
+	router.GET("/livez", healthHandler.Livez)   // Process liveness
+	router.GET("/readyz", healthHandler.Readyz) // Readiness including dependencies
+}
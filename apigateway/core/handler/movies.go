@@ -1,25 +1,20 @@
 package handler
 
 import (
-	"apigateway/core/proto"
+	"apigateway/core/domain"
 	"apigateway/core/usecases"
 	"apigateway/core/util"
 	"net/http"
+	"pkg/logger"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
-	"google.golang.org/grpc/codes"
 )
 
 const (
-	movieNotFoundMessage         = "movie not found"
-	internalServerErrorMessage   = "internal server error"
-	invalidPageNumberMessage     = "Invalid `pageNumber` value"
-	invalidResultsPerPageMessage = "Invalid `resultsPerPage` value"
-	invalidIdMessage             = "Invalid `id` value"
-	invalidRequestMessage        = "Invalid request"
-	pageNotFoundMessage          = "Page not found"
+	notANumberMessage  = "must be an integer"
+	invalidBodyMessage = "request body must be a JSON movie"
 )
 
 type MoviesHandler struct {
@@ -45,31 +40,19 @@ func (handler *MoviesHandler) GetMovies(context *gin.Context) {
 
 	pageNumberInt, errPageNumber := strconv.Atoi(pageNumber)
 	if errPageNumber != nil {
-		util.SendError(context, http.StatusBadRequest, invalidPageNumberMessage, errPageNumber)
+		util.SendProblem(context, util.NewFieldError(util.CodeInvalidPageNumber, "pageNumber", notANumberMessage))
 		return
 	}
 
 	resultsPerPageInt, errResultsPerPage := strconv.Atoi(resultsPerPage)
 	if errResultsPerPage != nil {
-		util.SendError(context, http.StatusBadRequest, invalidResultsPerPageMessage, errResultsPerPage)
-		return
-	}
-
-	movies, err := handler.UseCases.GetMovies(context, pageNumberInt, resultsPerPageInt)
-
-	if err != nil && err == util.ErrMoviePageNotFound {
-		util.SendError(context, http.StatusNotFound, pageNotFoundMessage, err)
-		return
-	}
-
-	if err != nil && util.IsErrInvalidParams(err) {
-		util.SendError(context, http.StatusBadRequest, invalidRequestMessage, err)
+		util.SendProblem(context, util.NewFieldError(util.CodeInvalidPageSize, "resultsPerPage", notANumberMessage))
 		return
 	}
 
+	movies, err := handler.UseCases.GetMovies(context.Request.Context(), pageNumberInt, resultsPerPageInt)
 	if err != nil {
-		handler.Logger.Error("could not get movies", zap.Error(err))
-		util.SendError(context, http.StatusInternalServerError, internalServerErrorMessage, err)
+		handler.sendError(context, "could not get movies", err)
 		return
 	}
 
@@ -92,23 +75,13 @@ func (handler *MoviesHandler) GetMovie(context *gin.Context) {
 
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
-		util.SendError(context, http.StatusBadRequest, invalidIdMessage, err)
-		return
-	}
-
-	movie, err := handler.UseCases.GetMovie(context, idInt)
-
-	grpcErr := util.ParseGRPCError(err)
-
-	if grpcErr != nil && grpcErr.Code == codes.NotFound {
-		util.SendError(context, http.StatusNotFound, movieNotFoundMessage, err)
+		util.SendProblem(context, util.NewFieldError(util.CodeInvalidID, "id", notANumberMessage))
 		return
 	}
 
+	movie, err := handler.UseCases.GetMovie(context.Request.Context(), idInt)
 	if err != nil {
-		code, message, details := util.GRPCToZap(grpcErr)
-		handler.Logger.Error("Internal Server Error", code, message, details)
-		util.SendError(context, http.StatusInternalServerError, internalServerErrorMessage, err)
+		handler.sendError(context, "could not get movie", err)
 		return
 	}
 
@@ -116,23 +89,16 @@ func (handler *MoviesHandler) GetMovie(context *gin.Context) {
 }
 
 func (handler *MoviesHandler) CreateMovie(context *gin.Context) {
-	var movie proto.Movie
+	var movie domain.Movie
 
 	if err := context.ShouldBindJSON(&movie); err != nil {
-		util.SendError(context, http.StatusBadRequest, invalidRequestMessage, err)
-		return
-	}
-
-	created, err := handler.UseCases.CreateMovie(context, &movie)
-
-	if err != nil && util.IsInvalidBody(err) {
-		util.SendError(context, http.StatusBadRequest, internalServerErrorMessage, err)
+		util.SendProblem(context, util.NewAPIError(util.CodeInvalidRequest, invalidBodyMessage))
 		return
 	}
 
+	created, err := handler.UseCases.CreateMovie(context.Request.Context(), &movie)
 	if err != nil {
-		handler.Logger.Error("Internal Server Error", zap.Error(err))
-		util.SendError(context, http.StatusInternalServerError, internalServerErrorMessage, err)
+		handler.sendError(context, "could not create movie", err)
 		return
 	}
 
@@ -154,27 +120,34 @@ func (handler *MoviesHandler) DeleteMovie(context *gin.Context) {
 
 	idInt, err := strconv.Atoi(id)
 	if err != nil {
-		util.SendError(context, http.StatusBadRequest, invalidIdMessage, err)
+		util.SendProblem(context, util.NewFieldError(util.CodeInvalidID, "id", notANumberMessage))
 		return
 	}
 
-	errDelete := handler.UseCases.DeleteMovie(context, idInt)
-
-	grpcErr := util.ParseGRPCError(errDelete)
-
-	if grpcErr != nil && grpcErr.Code == codes.NotFound {
-		util.SendError(context, http.StatusNotFound, movieNotFoundMessage, errDelete)
+	if err := handler.UseCases.DeleteMovie(context.Request.Context(), idInt); err != nil {
+		handler.sendError(context, "could not delete movie", err)
 		return
 	}
 
-	if errDelete != nil {
-		code, message, details := util.GRPCToZap(grpcErr)
-		handler.Logger.Error("Internal Server Error", code, message, details)
-		util.SendError(context, http.StatusInternalServerError, movieNotFoundMessage, errDelete)
-		return
+	util.SendSuccess(context, http.StatusNoContent, nil)
+}
+
+// sendError maps err into the error catalogue and only logs the ones that
+// are our fault, together with the upstream gRPC status if there is one.
+func (handler *MoviesHandler) sendError(context *gin.Context, message string, err error) {
+	apiErr := util.ToAPIError(err)
+
+	if apiErr.Status >= http.StatusInternalServerError {
+		fields := []zap.Field{zap.String("error_code", string(apiErr.Code)), zap.Error(err)}
+		if grpcErr := util.ParseGRPCError(err); grpcErr.IsStatus {
+			code, grpcMessage, details := util.GRPCToZap(grpcErr)
+			fields = append(fields, code, grpcMessage, details)
+		}
+
+		logger.WithContext(context.Request.Context(), handler.Logger).Error(message, fields...)
 	}
 
-	util.SendSuccess(context, http.StatusNoContent, nil)
+	util.SendProblem(context, apiErr)
 }
 
 // @Summary Deletar filme por ID
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"apigateway/core/app"
+	"apigateway/core/config"
+	"apigateway/pkg/metrics"
+	"net/http"
+	"net/http/httptest"
+	"pkg/logger"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAdminEndpointsRequireToken(t *testing.T) {
+	t.Setenv("ADMIN_TOKEN", "s3cret")
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	_, levels := logger.New("production", logger.Options{Levels: "grpc=warn"})
+	server := metrics.NewServer("0", app.AdminHandlers(&cfg, levels))
+
+	request := func(method, target, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	for _, token := range []string{"", "wrong"} {
+		response := request(http.MethodGet, "/config", token, "")
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+		response = request(http.MethodPut, "/log/level", token, `{"level":"debug"}`)
+		assert.Equal(t, http.StatusUnauthorized, response.Code)
+		assert.Equal(t, "Bearer", response.Header().Get("WWW-Authenticate"))
+	}
+
+	response := request(http.MethodGet, "/config", "s3cret", "")
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.NotContains(t, response.Body.String(), "s3cret", "the token itself is redacted")
+	assert.Equal(t, zapcore.InfoLevel, levels.Root().Level())
+
+	response = request(http.MethodPut, "/log/level?logger=grpc", "s3cret", `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"level":"debug"}`, response.Body.String())
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("grpc"))
+
+	response = request(http.MethodGet, "/log/level", "s3cret", "")
+	assert.JSONEq(t, `{"level":"info"}`, response.Body.String())
+}
+
+func TestAdminEndpointsNeedAToken(t *testing.T) {
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	_, levels := logger.New("production", logger.Options{})
+	server := metrics.NewServer("0", app.AdminHandlers(&cfg, levels))
+
+	for _, target := range []string{"/config", "/log/level"} {
+		recorder := httptest.NewRecorder()
+		server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, http.StatusNotFound, recorder.Code, target)
+	}
+}
+
+func TestLogLevelsValidated(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "verbose")
+	_, err := config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_LEVEL (env): must be one of debug, info, warn, error")
+
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_LEVELS", "grpc=chatty")
+	_, err = config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "LOG_LEVELS: unrecognized level")
+}
@@ -0,0 +1,161 @@
+package clients
+
+import (
+	_ "apigateway/infra/clients"
+	"context"
+	"net"
+	"proto"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/status"
+)
+
+// replica is one movies server; it answers GetMovie with code.
+type replica struct {
+	proto.UnimplementedMovieServiceServer
+	address string
+	code    codes.Code
+	calls   atomic.Int32
+}
+
+func (replica *replica) GetMovie(context.Context, *proto.MovieIdRequest) (*proto.Movie, error) {
+	replica.calls.Add(1)
+	if replica.code != codes.OK {
+		return nil, status.Error(replica.code, "")
+	}
+	return &proto.Movie{Id: 1}, nil
+}
+
+func startReplica(t *testing.T, code codes.Code) *replica {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	replica := &replica{address: listener.Addr().String(), code: code}
+	server := grpc.NewServer()
+	proto.RegisterMovieServiceServer(server, replica)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return replica
+}
+
+func addresses(replicas ...*replica) resolver.State {
+	var state resolver.State
+	for _, replica := range replicas {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: replica.address})
+	}
+	return state
+}
+
+// dial talks to the replicas handed to the manual resolver through the
+// gateway balancer, which registers itself as movies_balancer.
+func dial(t *testing.T, policy string, initial resolver.State) (proto.MovieServiceClient, *manual.Resolver) {
+	builder := manual.NewBuilderWithScheme("balancertest")
+	builder.InitialState(initial)
+
+	conn, err := grpc.NewClient(builder.Scheme()+":///movies",
+		grpc.WithResolvers(builder),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"movies_balancer": {
+			"policy": "`+policy+`",
+			"consecutiveFailures": 2,
+			"baseEjectionTime": "1m",
+			"maxEjectionPercent": 50
+		}}]}`),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return proto.NewMovieServiceClient(conn), builder
+}
+
+func callN(client proto.MovieServiceClient, n int) {
+	for range n {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		client.GetMovie(ctx, &proto.MovieIdRequest{Id: 1}, grpc.WaitForReady(true))
+		cancel()
+	}
+}
+
+// reached waits until every replica has had at least one call, i.e. all
+// of them are READY in the picker.
+func reached(t *testing.T, client proto.MovieServiceClient, replicas ...*replica) {
+	require.Eventually(t, func() bool {
+		callN(client, 1)
+		for _, replica := range replicas {
+			if replica.calls.Load() == 0 {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestBalancerSpreadsCalls(t *testing.T) {
+	for _, policy := range []string{"round_robin", "least_request"} {
+		t.Run(policy, func(t *testing.T) {
+			first, second := startReplica(t, codes.OK), startReplica(t, codes.OK)
+			client, _ := dial(t, policy, addresses(first, second))
+
+			reached(t, client, first, second)
+			callN(client, 40)
+
+			assert.Greater(t, first.calls.Load(), int32(5))
+			assert.Greater(t, second.calls.Load(), int32(5))
+		})
+	}
+}
+
+func TestBalancerEjectsFailingReplica(t *testing.T) {
+	healthy, failing := startReplica(t, codes.OK), startReplica(t, codes.Unavailable)
+	client, _ := dial(t, "round_robin", addresses(healthy, failing))
+
+	reached(t, client, healthy, failing)
+	callN(client, 10)
+	ejectedAt := failing.calls.Load()
+	assert.Equal(t, int32(2), ejectedAt, "ejected after consecutiveFailures")
+
+	callN(client, 20)
+	assert.Equal(t, ejectedAt, failing.calls.Load(), "no calls while ejected")
+}
+
+func TestBalancerNeverEjectsMoreThanMaxPercent(t *testing.T) {
+	first, second := startReplica(t, codes.Unavailable), startReplica(t, codes.Unavailable)
+	client, _ := dial(t, "round_robin", addresses(first, second))
+
+	reached(t, client, first, second)
+	callN(client, 20)
+
+	assert.Greater(t, first.calls.Load()+second.calls.Load(), int32(15), "half of two replicas is one, the other keeps serving")
+}
+
+func TestBalancerForgetsRemovedReplicas(t *testing.T) {
+	replicas := []*replica{
+		startReplica(t, codes.Unavailable), startReplica(t, codes.Unavailable),
+		startReplica(t, codes.OK), startReplica(t, codes.OK),
+	}
+	client, builder := dial(t, "round_robin", addresses(replicas...))
+
+	reached(t, client, replicas...)
+	callN(client, 20)
+	require.Equal(t, int32(2), replicas[0].calls.Load())
+	require.Equal(t, int32(2), replicas[1].calls.Load(), "two of four replicas ejected, the 50% cap")
+
+	// Both ejected replicas go away; a new failing one joins. It must be
+	// ejectable: the removed ones no longer count against the cap.
+	added := []*replica{startReplica(t, codes.Unavailable), startReplica(t, codes.OK)}
+	builder.UpdateState(addresses(replicas[2], replicas[3], added[0], added[1]))
+
+	reached(t, client, added...)
+	callN(client, 20)
+	assert.Equal(t, int32(2), added[0].calls.Load())
+}
@@ -0,0 +1,132 @@
+package clients
+
+import (
+	"apigateway/infra/clients"
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	getMovie    = "/movies.MovieService/GetMovie"
+	healthCheck = "/grpc.health.v1.Health/Check"
+)
+
+// backend answers with code and counts the calls that got through.
+type backend struct {
+	code  codes.Code
+	calls atomic.Int32
+	block chan struct{}
+}
+
+func (backend *backend) invoke(ctx context.Context, method string, req, reply any, conn *grpc.ClientConn, opts ...grpc.CallOption) error {
+	backend.calls.Add(1)
+	if backend.block != nil {
+		<-backend.block
+	}
+	return status.Error(backend.code, "")
+}
+
+func call(breaker *clients.CircuitBreaker, backend *backend, method string) error {
+	return breaker.UnaryClientInterceptor()(context.Background(), method, nil, nil, nil, backend.invoke)
+}
+
+func tripped(err error) bool {
+	return status.Code(err) == codes.Unavailable && status.Convert(err).Message() == "movies service unavailable: circuit breaker open"
+}
+
+func TestBreakerOpensAfterConsecutiveFailures(t *testing.T) {
+	breaker := clients.NewCircuitBreaker(3, time.Hour, 1)
+	down := &backend{code: codes.Unavailable}
+
+	for range 3 {
+		assert.False(t, tripped(call(breaker, down, getMovie)))
+	}
+	assert.Equal(t, int32(3), down.calls.Load())
+
+	assert.True(t, tripped(call(breaker, down, getMovie)), "open: fails fast")
+	assert.Equal(t, int32(3), down.calls.Load(), "open: the backend isn't called")
+}
+
+func TestBreakerIgnoresNormalErrors(t *testing.T) {
+	breaker := clients.NewCircuitBreaker(2, time.Hour, 1)
+	down := &backend{code: codes.DeadlineExceeded}
+	notFound := &backend{code: codes.NotFound}
+
+	call(breaker, down, getMovie)
+	call(breaker, notFound, getMovie)
+	call(breaker, down, getMovie)
+	assert.False(t, tripped(call(breaker, notFound, getMovie)), "an answer resets the failure run")
+
+	for _, code := range []codes.Code{codes.NotFound, codes.InvalidArgument, codes.AlreadyExists, codes.Canceled} {
+		answer := &backend{code: code}
+		for range 3 {
+			assert.False(t, tripped(call(breaker, answer, getMovie)), code.String())
+		}
+	}
+}
+
+func TestBreakerHalfOpenProbes(t *testing.T) {
+	breaker := clients.NewCircuitBreaker(1, 20*time.Millisecond, 1)
+	down := &backend{code: codes.Unavailable}
+
+	call(breaker, down, getMovie)
+	require.True(t, tripped(call(breaker, down, getMovie)))
+	time.Sleep(30 * time.Millisecond)
+
+	probe := &backend{code: codes.OK, block: make(chan struct{})}
+	result := make(chan error, 1)
+	go func() { result <- call(breaker, probe, getMovie) }()
+	require.Eventually(t, func() bool { return probe.calls.Load() == 1 }, time.Second, time.Millisecond)
+
+	assert.True(t, tripped(call(breaker, &backend{code: codes.OK}, getMovie)), "only HalfOpenMaxCalls probes at a time")
+
+	close(probe.block)
+	require.NoError(t, <-result)
+
+	up := &backend{code: codes.OK}
+	for range 3 {
+		require.NoError(t, call(breaker, up, getMovie), "a successful probe closes the breaker")
+	}
+}
+
+func TestBreakerReopensWhenProbeFails(t *testing.T) {
+	breaker := clients.NewCircuitBreaker(3, 20*time.Millisecond, 2)
+	down := &backend{code: codes.Unavailable}
+
+	for range 3 {
+		call(breaker, down, getMovie)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	assert.False(t, tripped(call(breaker, down, getMovie)), "the probe reaches the backend")
+	assert.True(t, tripped(call(breaker, down, getMovie)), "one failed probe opens it again")
+}
+
+func TestBreakerLetsHealthChecksThrough(t *testing.T) {
+	breaker := clients.NewCircuitBreaker(1, 20*time.Millisecond, 1)
+	down := &backend{code: codes.Unavailable}
+
+	call(breaker, down, getMovie)
+	require.True(t, tripped(call(breaker, down, getMovie)))
+
+	health := &backend{code: codes.OK}
+	require.NoError(t, call(breaker, health, healthCheck), "an open breaker doesn't hide the backend from probes")
+	assert.Equal(t, int32(1), health.calls.Load())
+
+	for range 5 {
+		call(breaker, down, healthCheck)
+	}
+	time.Sleep(30 * time.Millisecond)
+
+	up := &backend{code: codes.OK}
+	require.NoError(t, call(breaker, up, getMovie), "health checks neither trip the breaker nor use the half-open slot")
+	assert.Equal(t, int32(1), up.calls.Load())
+}
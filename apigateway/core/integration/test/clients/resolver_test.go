@@ -0,0 +1,96 @@
+package clients
+
+import (
+	"apigateway/core/config"
+	"apigateway/infra/clients"
+	"context"
+	"net"
+	"proto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// startServing is startReplica plus the health service the gateway client
+// checks every endpoint with.
+func startServing(t *testing.T) *replica {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	replica := &replica{address: listener.Addr().String()}
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus(proto.MovieService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+
+	server := grpc.NewServer()
+	proto.RegisterMovieServiceServer(server, replica)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return replica
+}
+
+func newClient(t *testing.T, addresses ...string) *clients.MoviesGRPCClient {
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	cfg.GrpcServerAddress = strings.Join(addresses, ", ")
+	cfg.GrpcTLSCertFile = ""
+	cfg.GrpcResolveInterval = 50 * time.Millisecond
+
+	client, err := clients.NewGrpcClient(&cfg, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestResolverUsesEveryListedAddress(t *testing.T) {
+	first, second := startServing(t), startServing(t)
+	client := newClient(t, first.address, second.address)
+
+	require.Eventually(t, func() bool {
+		client.GetMovie(context.Background(), 1)
+		return first.calls.Load() > 0 && second.calls.Load() > 0
+	}, 5*time.Second, time.Millisecond)
+}
+
+func TestResolverLooksUpHostNames(t *testing.T) {
+	replica := startServing(t)
+	_, port, err := net.SplitHostPort(replica.address)
+	require.NoError(t, err)
+
+	client := newClient(t, net.JoinHostPort("localhost", port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, client.CheckHealth(ctx))
+	_, err = client.GetMovie(ctx, 1)
+	require.NoError(t, err)
+}
+
+func TestResolverSkipsBrokenEndpoints(t *testing.T) {
+	replica := startServing(t)
+	client := newClient(t, "missing-port", replica.address)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.GetMovie(ctx, 1)
+	require.NoError(t, err, "one bad entry doesn't take the others down")
+}
+
+func TestResolverReportsWhenNothingResolves(t *testing.T) {
+	client := newClient(t, "missing-port")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	_, err := client.GetMovie(ctx, 1)
+	assert.Contains(t, []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, status.Code(err))
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"apigateway/core/config"
+	"os"
+	"path/filepath"
+	"pkg/conf"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sourceOf(all conf.Settings, key string) string {
+	for _, setting := range all {
+		if setting.Key == key {
+			return setting.Source
+		}
+	}
+	return ""
+}
+
+func TestGatewayConfigDefaults(t *testing.T) {
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "8080", cfg.ListenPort)
+	assert.Equal(t, "round_robin", cfg.GrpcLbPolicy)
+	assert.True(t, cfg.HTTPRedirect)
+	assert.Equal(t, 365*24*time.Hour, cfg.HSTSMaxAge)
+	assert.Equal(t, conf.SourceDefault, sourceOf(cfg.Settings, "GRPC_SERVER"))
+}
+
+func TestGatewayConfigLayers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("grpc_server: movies:9090\ngrpc_retry_max_attempts: 4\nlisten_port: 8081\n"), 0o600))
+
+	t.Setenv(conf.FileEnv, path)
+	t.Setenv("LISTEN_PORT", "8082")
+
+	cfg, err := config.Load([]string{"-grpc-lb-policy", "least_request"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "movies:9090", cfg.GrpcServerAddress)
+	assert.Equal(t, 4, cfg.GrpcRetryMaxAttempts)
+	assert.Equal(t, "8082", cfg.ListenPort)
+	assert.Equal(t, "least_request", cfg.GrpcLbPolicy)
+	assert.Equal(t, conf.SourceFile, sourceOf(cfg.Settings, "GRPC_SERVER"))
+	assert.Equal(t, conf.SourceEnv, sourceOf(cfg.Settings, "LISTEN_PORT"))
+	assert.Equal(t, conf.SourceFlag, sourceOf(cfg.Settings, "GRPC_LB_POLICY"))
+}
+
+func TestGatewayConfigValidation(t *testing.T) {
+	t.Setenv("GRPC_LB_POLICY", "random")
+	t.Setenv("OUTLIER_MAX_EJECTION_PERCENT", "150")
+	t.Setenv("HTTPS_PORT", "https")
+
+	_, err := config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPC_LB_POLICY (env): must be one of round_robin, least_request")
+	assert.Contains(t, err.Error(), "OUTLIER_MAX_EJECTION_PERCENT (env): must be at most 100, got 150")
+	assert.Contains(t, err.Error(), `HTTPS_PORT (env): must be a port number, got "https"`)
+
+	// Empty variables fall back to the default, as with compose pass-through.
+	t.Setenv("GRPC_LB_POLICY", "")
+	t.Setenv("OUTLIER_MAX_EJECTION_PERCENT", "")
+	t.Setenv("HTTPS_PORT", "")
+	t.Setenv("HTTP_TLS_CERT_FILE", "/certs/gateway.crt")
+	t.Setenv("GRPC_RETRY_INITIAL_BACKOFF", "2s")
+
+	_, err = config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "HTTP_TLS_CERT_FILE and HTTP_TLS_KEY_FILE must be set together")
+	assert.Contains(t, err.Error(), "GRPC_RETRY_INITIAL_BACKOFF must not exceed GRPC_RETRY_MAX_BACKOFF")
+}
+
+func TestGatewayConfigRejectsNonPositiveIntervals(t *testing.T) {
+	for _, key := range []string{"GRPC_RESOLVE_INTERVAL", "BREAKER_OPEN_TIMEOUT", "OUTLIER_BASE_EJECTION_TIME"} {
+		for _, value := range []string{"0s", "-1s"} {
+			t.Setenv(key, value)
+			_, err := config.Load(nil)
+			require.Error(t, err, "%s=%s", key, value)
+			assert.Contains(t, err.Error(), "GRPC_RESOLVE_INTERVAL, BREAKER_OPEN_TIMEOUT and OUTLIER_BASE_EJECTION_TIME must be positive")
+		}
+		t.Setenv(key, "")
+	}
+}
+
+func TestGatewayConfigRejectsZeroTLSReloadInterval(t *testing.T) {
+	t.Setenv("TLS_RELOAD_INTERVAL", "0s")
+
+	_, err := config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "TLS_RELOAD_INTERVAL must be positive")
+}
+
+func TestGatewayConfigRequiresCAForMutualTLS(t *testing.T) {
+	t.Setenv("GRPC_TLS_CERT_FILE", "/certs/gateway.crt")
+	t.Setenv("GRPC_TLS_KEY_FILE", "/certs/gateway.key")
+
+	_, err := config.Load(nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GRPC_TLS_CA_FILE is required with GRPC_TLS_CERT_FILE")
+
+	t.Setenv("GRPC_TLS_CA_FILE", "/certs/ca.crt")
+	_, err = config.Load(nil)
+	require.NoError(t, err)
+
+	// The public HTTPS listener still works without a CA.
+	t.Setenv("HTTP_TLS_CERT_FILE", "/certs/public.crt")
+	t.Setenv("HTTP_TLS_KEY_FILE", "/certs/public.key")
+	_, err = config.Load(nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"apigateway/core/handler"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type checker struct {
+	name string
+	err  error
+	wait time.Duration
+}
+
+func (checker checker) Name() string {
+	return checker.name
+}
+
+func (checker checker) CheckHealth(ctx context.Context) error {
+	select {
+	case <-time.After(checker.wait):
+		return checker.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type healthBody struct {
+	Status string                              `json:"status"`
+	Checks map[string]handler.DependencyStatus `json:"checks"`
+}
+
+func healthRouter(readiness *handler.Readiness, checkers ...handler.HealthChecker) *gin.Engine {
+	return healthRouterWithLog(readiness, zap.NewNop(), checkers...)
+}
+
+func healthRouterWithLog(readiness *handler.Readiness, log *zap.Logger, checkers ...handler.HealthChecker) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	healthHandler := handler.NewHealthHandler(readiness, 50*time.Millisecond, log, checkers...)
+	router := gin.New()
+	handler.RegisterHealthRoutes(router, healthHandler)
+	router.GET("/v1/health", healthHandler.LegacyHealth)
+	return router
+}
+
+func probe(t *testing.T, router http.Handler, path string) (*httptest.ResponseRecorder, healthBody) {
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body healthBody
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body), res.Body.String())
+	return res, body
+}
+
+func TestReadyzReportsEachDependency(t *testing.T) {
+	router := healthRouter(handler.NewReadiness(), checker{name: "movies"}, checker{name: "cache"})
+
+	res, body := probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "ready", body.Status)
+	require.Len(t, body.Checks, 2)
+	assert.Equal(t, "up", body.Checks["movies"].Status)
+	assert.NotEmpty(t, body.Checks["movies"].Latency)
+}
+
+func TestReadyzFailsWhenADependencyIsDown(t *testing.T) {
+	core, logs := observer.New(zap.WarnLevel)
+	router := healthRouterWithLog(handler.NewReadiness(), zap.New(core),
+		checker{name: "movies", err: errors.New("dial tcp 10.0.3.7:9090: connection refused")},
+		checker{name: "slow", wait: time.Second},
+		checker{name: "cache"},
+	)
+
+	res, body := probe(t, router, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "not_ready", body.Status)
+	assert.Equal(t, handler.DependencyStatus{Status: "down", Latency: body.Checks["movies"].Latency, Error: "unavailable"}, body.Checks["movies"])
+	assert.Equal(t, handler.DependencyStatus{Status: "down", Latency: body.Checks["slow"].Latency, Error: "timeout"}, body.Checks["slow"],
+		"checks are cut off at the timeout")
+	assert.Equal(t, "up", body.Checks["cache"].Status)
+	assert.NotContains(t, res.Body.String(), "10.0.3.7", "dependency errors stay out of the public body")
+
+	entries := logs.FilterField(zap.String("dependency", "movies")).All()
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].ContextMap()["error"], "10.0.3.7", "the full error is logged")
+}
+
+func TestReadyzWhileDraining(t *testing.T) {
+	readiness := handler.NewReadiness()
+	readiness.SetReady(false)
+
+	res, body := probe(t, healthRouter(readiness, checker{name: "movies"}), "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+	assert.Equal(t, "draining", body.Status)
+	assert.Empty(t, body.Checks, "dependencies aren't probed once draining")
+}
+
+func TestLivezIgnoresDependencies(t *testing.T) {
+	readiness := handler.NewReadiness()
+	readiness.SetReady(false)
+
+	res, body := probe(t, healthRouter(readiness, checker{name: "movies", err: errors.New("down")}), "/livez")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "alive", body.Status)
+}
+
+func TestLegacyHealthIsReadyzAlias(t *testing.T) {
+	readiness := handler.NewReadiness()
+	router := healthRouter(readiness, checker{name: "movies"})
+
+	res, body := probe(t, router, "/v1/health")
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "ready", body.Status)
+	assert.Equal(t, "true", res.Header().Get("Deprecation"))
+	assert.Contains(t, res.Header().Get("Link"), "</readyz>")
+
+	readiness.SetReady(false)
+	res, _ = probe(t, router, "/v1/health")
+	assert.Equal(t, http.StatusServiceUnavailable, res.Code)
+}
@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"apigateway/core/domain"
+	"apigateway/core/handler"
+	"apigateway/core/usecases"
+	"apigateway/core/util"
+	"apigateway/infra/clients/fake"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   struct {
+		Code       string                `json:"code"`
+		Violations []util.FieldViolation `json:"violations"`
+	} `json:"error"`
+}
+
+func newRouter(client usecases.MoviesClient) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	handler.RegisterMoviesRoutes(router.Group("/v1"), usecases.NewMoviesUseCases(client, zap.NewNop()), zap.NewNop())
+	return router
+}
+
+func seededClient() *fake.MoviesClient {
+	client := fake.NewMoviesClient()
+	client.Seed(
+		&domain.Movie{Id: 1, Title: "The Matrix", Year: "1999"},
+		&domain.Movie{Id: 2, Title: "Inception", Year: "2010"},
+		&domain.Movie{Id: 3, Title: "Interstellar", Year: "2014"},
+	)
+	return client
+}
+
+func request(test *testing.T, router http.Handler, method, path, body string) (*httptest.ResponseRecorder, envelope) {
+	test.Helper()
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	var decoded envelope
+	if res.Body.Len() > 0 {
+		require.NoError(test, json.Unmarshal(res.Body.Bytes(), &decoded), res.Body.String())
+	}
+
+	return res, decoded
+}
+
+func TestGetMovies(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodGet, "/v1/movies?pageNumber=1&resultsPerPage=2", "")
+	require.Equal(test, http.StatusOK, res.Code)
+	assert.True(test, body.Success)
+
+	var list domain.MovieList
+	require.NoError(test, json.Unmarshal(body.Data, &list))
+	assert.Equal(test, uint32(3), list.Total)
+	assert.Equal(test, uint32(2), list.Results)
+	assert.True(test, list.More)
+	require.Len(test, list.Movies, 2)
+	assert.Equal(test, "Interstellar", list.Movies[0].Title)
+	assert.Equal(test, "Inception", list.Movies[1].Title)
+}
+
+func TestGetMoviesLastPage(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodGet, "/v1/movies?pageNumber=2&resultsPerPage=2", "")
+	require.Equal(test, http.StatusOK, res.Code)
+
+	var list domain.MovieList
+	require.NoError(test, json.Unmarshal(body.Data, &list))
+	assert.False(test, list.More)
+	require.Len(test, list.Movies, 1)
+	assert.Equal(test, "The Matrix", list.Movies[0].Title)
+}
+
+func TestGetMoviesErrors(test *testing.T) {
+	cases := []struct {
+		name   string
+		query  string
+		status int
+		code   util.ErrorCode
+	}{
+		{"page not a number", "pageNumber=abc", http.StatusBadRequest, util.CodeInvalidPageNumber},
+		{"page below one", "pageNumber=0", http.StatusBadRequest, util.CodeInvalidPageNumber},
+		{"page size not a number", "resultsPerPage=abc", http.StatusBadRequest, util.CodeInvalidPageSize},
+		{"page size too short", "resultsPerPage=1", http.StatusBadRequest, util.CodeInvalidPageSize},
+		{"page size too long", "resultsPerPage=21", http.StatusBadRequest, util.CodeInvalidPageSize},
+		{"page past the end", "pageNumber=10&resultsPerPage=2", http.StatusNotFound, util.CodePageNotFound},
+	}
+
+	router := newRouter(seededClient())
+	for _, testCase := range cases {
+		test.Run(testCase.name, func(test *testing.T) {
+			res, body := request(test, router, http.MethodGet, "/v1/movies?"+testCase.query, "")
+
+			assert.Equal(test, testCase.status, res.Code)
+			assert.False(test, body.Success)
+			assert.Equal(test, string(testCase.code), body.Error.Code)
+		})
+	}
+}
+
+func TestGetMovie(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodGet, "/v1/movies/2", "")
+	require.Equal(test, http.StatusOK, res.Code)
+
+	var movie domain.Movie
+	require.NoError(test, json.Unmarshal(body.Data, &movie))
+	assert.Equal(test, domain.Movie{Id: 2, Title: "Inception", Year: "2010"}, movie)
+}
+
+func TestGetMovieErrors(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodGet, "/v1/movies/abc", "")
+	assert.Equal(test, http.StatusBadRequest, res.Code)
+	assert.Equal(test, string(util.CodeInvalidID), body.Error.Code)
+
+	res, body = request(test, router, http.MethodGet, "/v1/movies/999", "")
+	assert.Equal(test, http.StatusNotFound, res.Code)
+	assert.Equal(test, string(util.CodeMovieNotFound), body.Error.Code)
+}
+
+func TestCreateMovie(test *testing.T) {
+	client := seededClient()
+	router := newRouter(client)
+
+	res, body := request(test, router, http.MethodPost, "/v1/movies", `{"title":"Alice in Wonderland","year":"2010"}`)
+	require.Equal(test, http.StatusCreated, res.Code)
+
+	var created domain.Movie
+	require.NoError(test, json.Unmarshal(body.Data, &created))
+	assert.Equal(test, uint32(4), created.Id)
+	assert.Equal(test, "Alice in Wonderland", created.Title)
+
+	stored, err := client.GetMovie(test.Context(), created.Id)
+	require.NoError(test, err)
+	assert.Equal(test, "2010", stored.Year)
+}
+
+func TestCreateMovieErrors(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodPost, "/v1/movies", `{"title":`)
+	assert.Equal(test, http.StatusBadRequest, res.Code)
+	assert.Equal(test, string(util.CodeInvalidRequest), body.Error.Code)
+
+	res, body = request(test, router, http.MethodPost, "/v1/movies", `{"title":"Alice in Wonderland"}`)
+	assert.Equal(test, http.StatusBadRequest, res.Code)
+	assert.Equal(test, string(util.CodeValidationFailed), body.Error.Code)
+	assert.Equal(test, []util.FieldViolation{{Field: "year", Description: util.ErrYearEmpty.Error()}}, body.Error.Violations)
+}
+
+func TestDeleteMovie(test *testing.T) {
+	client := seededClient()
+	router := newRouter(client)
+
+	res, _ := request(test, router, http.MethodDelete, "/v1/movies/1", "")
+	assert.Equal(test, http.StatusNoContent, res.Code)
+
+	_, err := client.GetMovie(test.Context(), 1)
+	assert.ErrorIs(test, err, util.ErrMovieNotFound)
+}
+
+func TestDeleteMovieErrors(test *testing.T) {
+	router := newRouter(seededClient())
+
+	res, body := request(test, router, http.MethodDelete, "/v1/movies/abc", "")
+	assert.Equal(test, http.StatusBadRequest, res.Code)
+	assert.Equal(test, string(util.CodeInvalidID), body.Error.Code)
+
+	res, body = request(test, router, http.MethodDelete, "/v1/movies/999", "")
+	assert.Equal(test, http.StatusNotFound, res.Code)
+	assert.Equal(test, string(util.CodeMovieNotFound), body.Error.Code)
+}
+
+func TestUpstreamFailures(test *testing.T) {
+	client := seededClient()
+	router := newRouter(client)
+
+	client.FailWith(util.NewAPIError(util.CodeServiceUnavailable, ""))
+	for _, route := range []struct{ method, path, body string }{
+		{http.MethodGet, "/v1/movies", ""},
+		{http.MethodGet, "/v1/movies/1", ""},
+		{http.MethodPost, "/v1/movies", `{"title":"Alice in Wonderland","year":"2010"}`},
+		{http.MethodDelete, "/v1/movies/1", ""},
+	} {
+		res, body := request(test, router, route.method, route.path, route.body)
+		assert.Equal(test, http.StatusServiceUnavailable, res.Code, route.method+" "+route.path)
+		assert.Equal(test, string(util.CodeServiceUnavailable), body.Error.Code)
+	}
+
+	client.FailWith(errors.New("connection reset"))
+	res, body := request(test, router, http.MethodGet, "/v1/movies/1", "")
+	assert.Equal(test, http.StatusInternalServerError, res.Code)
+	assert.Equal(test, string(util.CodeInternal), body.Error.Code)
+}
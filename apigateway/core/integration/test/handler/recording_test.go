@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"apigateway/core/util"
+	"apigateway/infra/clients/recording"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(test *testing.T) {
+	recorder := recording.NewRecorder(seededClient())
+	recordingRouter := newRouter(recorder)
+
+	calls := []struct{ method, path, body string }{
+		{http.MethodGet, "/v1/movies?pageNumber=1&resultsPerPage=2", ""},
+		{http.MethodGet, "/v1/movies/999", ""},
+		{http.MethodPost, "/v1/movies", `{"title":"Alice in Wonderland","year":"2010"}`},
+		{http.MethodDelete, "/v1/movies/1", ""},
+	}
+
+	var recorded []*httptest.ResponseRecorder
+	for _, call := range calls {
+		res, _ := request(test, recordingRouter, call.method, call.path, call.body)
+		recorded = append(recorded, res)
+	}
+
+	path := filepath.Join(test.TempDir(), "movies.json")
+	require.NoError(test, recorder.Save(path))
+
+	replayer, err := recording.NewReplayerFromFile(path)
+	require.NoError(test, err)
+	replayingRouter := newRouter(replayer)
+
+	for i, call := range calls {
+		res, _ := request(test, replayingRouter, call.method, call.path, call.body)
+		assert.Equal(test, recorded[i].Code, res.Code, call.method+" "+call.path)
+		assert.Equal(test, recorded[i].Body.String(), res.Body.String(), call.method+" "+call.path)
+	}
+	assert.Zero(test, replayer.Remaining())
+
+	res, body := request(test, replayingRouter, http.MethodGet, "/v1/movies/2", "")
+	assert.Equal(test, http.StatusInternalServerError, res.Code)
+	assert.Equal(test, string(util.CodeInternal), body.Error.Code)
+}
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"apigateway/pkg/metrics"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T) string {
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestGinMiddlewareRecordsRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(metrics.GinMiddleware())
+	router.GET("/test/metrics/:id", func(context *gin.Context) { context.Status(http.StatusAccepted) })
+
+	for _, target := range []string{"/test/metrics/1", "/test/metrics/2", "/test/nowhere"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	body := scrape(t)
+	assert.Contains(t, body, `apigateway_http_request_duration_seconds_count{method="GET",route="/test/metrics/:id",status="202"} 2`,
+		"the route template, not the path, keeps the label set bounded")
+	assert.Contains(t, body, `apigateway_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+}
+
+func TestUnaryClientInterceptorRecordsCode(t *testing.T) {
+	interceptor := metrics.UnaryClientInterceptor()
+	invoker := func(code codes.Code) grpc.UnaryInvoker {
+		return func(ctx context.Context, method string, req, reply any, conn *grpc.ClientConn, opts ...grpc.CallOption) error {
+			return status.Error(code, "")
+		}
+	}
+
+	err := interceptor(context.Background(), "/test.Metrics/Client", nil, nil, nil, invoker(codes.Unavailable))
+	assert.Equal(t, codes.Unavailable, status.Code(err), "the call's error is passed through")
+	require.NoError(t, interceptor(context.Background(), "/test.Metrics/Client", nil, nil, nil, invoker(codes.OK)))
+
+	body := scrape(t)
+	assert.Contains(t, body, `apigateway_grpc_client_handling_seconds_count{code="Unavailable",method="/test.Metrics/Client"} 1`)
+	assert.Contains(t, body, `apigateway_grpc_client_handling_seconds_count{code="OK",method="/test.Metrics/Client"} 1`)
+}
+
+func TestServerRoutes(t *testing.T) {
+	server := metrics.NewServer("9100", map[string]http.Handler{
+		"/extra": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }),
+	})
+	assert.Equal(t, ":9100", server.Addr)
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "go_goroutines")
+
+	recorder = httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/extra", nil))
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"apigateway/core/middleware"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedirectToHTTPS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) })
+
+	cases := []struct {
+		port, host, target string
+		status             int
+		location           string
+	}{
+		{"8443", "example.com:8080", "/v1/movies?page=2", http.StatusPermanentRedirect, "https://example.com:8443/v1/movies?page=2"},
+		{"443", "example.com", "/v1/movies", http.StatusPermanentRedirect, "https://example.com/v1/movies"},
+		{"8443", "localhost:8080", "/livez", http.StatusTeapot, ""},
+		{"8443", "localhost:8080", "/readyz", http.StatusTeapot, ""},
+	}
+
+	for _, test := range cases {
+		request := httptest.NewRequest(http.MethodGet, test.target, nil)
+		request.Host = test.host
+		recorder := httptest.NewRecorder()
+
+		middleware.RedirectToHTTPS(test.port, next).ServeHTTP(recorder, request)
+
+		assert.Equal(t, test.status, recorder.Code, test.target)
+		assert.Equal(t, test.location, recorder.Header().Get("Location"), test.target)
+	}
+}
+
+func TestHSTSOnlyOverTLS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.HSTS(time.Hour))
+	router.GET("/", func(context *gin.Context) {})
+
+	plain := httptest.NewRecorder()
+	router.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, plain.Header().Get("Strict-Transport-Security"))
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.TLS = &tls.ConnectionState{}
+	secure := httptest.NewRecorder()
+	router.ServeHTTP(secure, request)
+	assert.Equal(t, "max-age=3600; includeSubDomains", secure.Header().Get("Strict-Transport-Security"))
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"apigateway/core/middleware"
+	"apigateway/core/util"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"pkg/requestid"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newRouter(log *zap.Logger) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.AccessLog(log))
+	router.GET("/ok", func(context *gin.Context) {
+		context.String(http.StatusOK, requestid.FromContext(context.Request.Context()))
+	})
+	router.GET("/fail", func(context *gin.Context) {
+		util.SendProblem(context, util.NewAPIError(util.CodeMovieNotFound, ""))
+	})
+	return router
+}
+
+func get(router http.Handler, target, id string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+	return res
+}
+
+func TestRequestIDAcceptsCallerID(t *testing.T) {
+	res := get(newRouter(zap.NewNop()), "/ok", "abc-123")
+
+	assert.Equal(t, "abc-123", res.Header().Get(requestid.Header))
+	assert.Equal(t, "abc-123", res.Body.String(), "the handler sees the same ID in its context")
+}
+
+func TestRequestIDReplacesInvalidID(t *testing.T) {
+	for _, id := range []string{"", "has space", strings.Repeat("a", 129), "tab\there"} {
+		res := get(newRouter(zap.NewNop()), "/ok", id)
+
+		generated := res.Header().Get(requestid.Header)
+		assert.NotEqual(t, id, generated)
+		assert.True(t, requestid.IsValid(generated), id)
+		assert.Len(t, generated, 32)
+	}
+}
+
+func TestRequestIDInErrorEnvelope(t *testing.T) {
+	res := get(newRouter(zap.NewNop()), "/fail", "err-1")
+	require.Equal(t, http.StatusNotFound, res.Code)
+
+	var body struct {
+		Error struct {
+			RequestID string `json:"request_id"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(res.Body.Bytes(), &body))
+	assert.Equal(t, "err-1", body.Error.RequestID)
+}
+
+func TestAccessLogCarriesRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	router := newRouter(zap.New(core))
+
+	get(router, "/ok", "log-1")
+	get(router, "/fail", "log-2")
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+
+	first := entries[0].ContextMap()
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "log-1", first["request_id"])
+	assert.Equal(t, "/ok", first["route"])
+	assert.Equal(t, int64(http.StatusOK), first["status"])
+
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level, "client errors are warnings")
+	assert.Equal(t, "log-2", entries[1].ContextMap()["request_id"])
+}
+
+func TestRequestIDClientInterceptorForwardsID(t *testing.T) {
+	var forwarded []string
+	invoker := func(ctx context.Context, method string, req, reply any, conn *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		forwarded = md.Get(requestid.MetadataKey)
+		return nil
+	}
+	interceptor := middleware.RequestIDClientInterceptor()
+
+	ctx := requestid.NewContext(context.Background(), "fwd-1")
+	require.NoError(t, interceptor(ctx, "/movies.Movies/Get", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"fwd-1"}, forwarded)
+
+	require.NoError(t, interceptor(context.Background(), "/movies.Movies/Get", nil, nil, nil, invoker))
+	assert.Empty(t, forwarded, "nothing is sent without an ID")
+}
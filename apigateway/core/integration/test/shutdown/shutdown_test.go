@@ -0,0 +1,125 @@
+package shutdown
+
+import (
+	"apigateway/core/handler"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type gateway struct {
+	server    *http.Server
+	url       string
+	readiness *handler.Readiness
+	started   chan struct{}
+	release   chan struct{}
+}
+
+// start serves the health routes plus /slow, which holds the request
+// until release is closed.
+func start(t *testing.T) *gateway {
+	gin.SetMode(gin.TestMode)
+
+	gw := &gateway{
+		readiness: handler.NewReadiness(),
+		started:   make(chan struct{}),
+		release:   make(chan struct{}),
+	}
+
+	router := gin.New()
+	handler.RegisterHealthRoutes(router, handler.NewHealthHandler(gw.readiness, time.Second, zap.NewNop()))
+	router.GET("/slow", func(context *gin.Context) {
+		close(gw.started)
+		<-gw.release
+		context.String(http.StatusOK, "done")
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	gw.server = &http.Server{Handler: router}
+	gw.url = "http://" + listener.Addr().String()
+	go gw.server.Serve(listener)
+	t.Cleanup(func() { gw.server.Close() })
+
+	return gw
+}
+
+func get(t *testing.T, url string) (int, string) {
+	response, err := http.Get(url)
+	require.NoError(t, err)
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+	return response.StatusCode, string(body)
+}
+
+func TestReadinessFlipsBeforeDraining(t *testing.T) {
+	gw := start(t)
+
+	code, _ := get(t, gw.url+"/readyz")
+	assert.Equal(t, http.StatusOK, code)
+
+	gw.readiness.SetReady(false)
+
+	code, body := get(t, gw.url+"/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+
+	var decoded struct {
+		Status string `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(body), &decoded))
+	assert.Equal(t, "draining", decoded.Status)
+
+	code, _ = get(t, gw.url+"/livez")
+	assert.Equal(t, http.StatusOK, code, "draining is not a reason to restart the process")
+}
+
+func TestShutdownDrainsInFlightRequests(t *testing.T) {
+	gw := start(t)
+
+	done := make(chan *http.Response, 1)
+	go func() {
+		response, err := http.Get(gw.url + "/slow")
+		if err != nil {
+			response = &http.Response{StatusCode: 0}
+		}
+		done <- response
+	}()
+	<-gw.started
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		shutdownErr <- gw.server.Shutdown(ctx)
+	}()
+
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown returned while a request was in flight: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(gw.release)
+	response := <-done
+	require.Equal(t, http.StatusOK, response.StatusCode, "the in-flight request completes")
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+	require.NoError(t, <-shutdownErr)
+
+	_, err = http.Get(gw.url + "/livez")
+	assert.Error(t, err, "no new connections after shutdown")
+}
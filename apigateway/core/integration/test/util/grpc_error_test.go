@@ -0,0 +1,113 @@
+package util
+
+import (
+	"apigateway/core/util"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func statusError(t *testing.T, code codes.Code, details ...protoadapt.MessageV1) error {
+	st, err := status.New(code, "upstream message").WithDetails(details...)
+	require.NoError(t, err)
+	return st.Err()
+}
+
+func reason(code util.ErrorCode) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{Reason: string(code), Domain: "movies.sipub.tech"}
+}
+
+func TestParseGRPCErrorReadsDetails(t *testing.T) {
+	err := statusError(t, codes.InvalidArgument,
+		reason(util.CodeValidationFailed),
+		&errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "title", Description: "must not be empty"},
+		}},
+		&errdetails.ResourceInfo{ResourceType: "movie", ResourceName: "7"},
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(3 * time.Second)},
+	)
+
+	grpcErr := util.ParseGRPCError(err)
+	assert.True(t, grpcErr.IsStatus)
+	assert.Equal(t, codes.InvalidArgument, grpcErr.Code)
+	assert.Equal(t, string(util.CodeValidationFailed), grpcErr.Reason)
+	assert.Equal(t, []util.FieldViolation{{Field: "title", Description: "must not be empty"}}, grpcErr.Violations)
+	assert.Equal(t, &util.ResourceRef{Type: "movie", Name: "7"}, grpcErr.Resource)
+	assert.Equal(t, 3*time.Second, grpcErr.RetryAfter)
+}
+
+func TestParseGRPCErrorWithoutStatus(t *testing.T) {
+	assert.Nil(t, util.ParseGRPCError(nil))
+
+	grpcErr := util.ParseGRPCError(errors.New("dial failed"))
+	assert.False(t, grpcErr.IsStatus)
+	assert.Equal(t, codes.Unknown, grpcErr.Code)
+	assert.Equal(t, util.CodeInternal, grpcErr.APIError().Code)
+}
+
+func TestAPIErrorMapping(t *testing.T) {
+	cases := map[string]struct {
+		err    error
+		code   util.ErrorCode
+		status int
+	}{
+		"reason wins over code": {statusError(t, codes.Internal, reason(util.CodeMovieNotFound)), util.CodeMovieNotFound, http.StatusNotFound},
+		"unknown reason":        {statusError(t, codes.AlreadyExists, reason("SOMETHING_NEW")), util.CodeMovieConflict, http.StatusConflict},
+		"not found":             {statusError(t, codes.NotFound), util.CodeMovieNotFound, http.StatusNotFound},
+		"invalid argument":      {statusError(t, codes.InvalidArgument), util.CodeValidationFailed, http.StatusBadRequest},
+		"unavailable":           {statusError(t, codes.Unavailable), util.CodeServiceUnavailable, http.StatusServiceUnavailable},
+		"deadline":              {statusError(t, codes.DeadlineExceeded), util.CodeUpstreamTimeout, http.StatusGatewayTimeout},
+		"unmapped code":         {statusError(t, codes.PermissionDenied), util.CodeInternal, http.StatusInternalServerError},
+	}
+
+	for name, tc := range cases {
+		apiErr := util.ParseGRPCError(tc.err).APIError()
+		assert.Equal(t, tc.code, apiErr.Code, name)
+		assert.Equal(t, tc.status, apiErr.Status, name)
+		assert.NotContains(t, apiErr.Error(), "upstream message", "%s: the upstream message is never exposed", name)
+	}
+}
+
+func TestAPIErrorCarriesDetails(t *testing.T) {
+	err := statusError(t, codes.NotFound,
+		reason(util.CodeMovieNotFound),
+		&errdetails.ResourceInfo{ResourceType: "movie", ResourceName: "42"},
+	)
+
+	apiErr := util.ParseGRPCError(err).APIError()
+	assert.Equal(t, "movie 42: movie not found", apiErr.Detail)
+
+	err = statusError(t, codes.Unavailable,
+		reason(util.CodeServiceUnavailable),
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(1500 * time.Millisecond)},
+	)
+
+	apiErr = util.ParseGRPCError(err).APIError()
+	assert.Equal(t, 1500*time.Millisecond, apiErr.RetryAfter)
+	assert.Empty(t, apiErr.Detail, "no resource, no detail")
+}
+
+func TestRetryAfterHeaderRoundsUp(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	context, _ := gin.CreateTestContext(recorder)
+	context.Request = httptest.NewRequest(http.MethodGet, "/api/movies", nil)
+
+	apiErr := util.NewAPIError(util.CodeServiceUnavailable, "")
+	apiErr.RetryAfter = 1500 * time.Millisecond
+	util.SendProblem(context, apiErr)
+
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	assert.Equal(t, "2", recorder.Header().Get("Retry-After"))
+}
@@ -0,0 +1,27 @@
+package util
+
+import (
+	"apigateway/core/util"
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCancelledRequestsAreNotServerErrors(t *testing.T) {
+	for _, err := range []error{
+		status.Error(codes.Canceled, "context canceled"),
+		context.Canceled,
+		fmt.Errorf("get movie: %w", context.Canceled),
+	} {
+		apiErr := util.ToAPIError(err)
+		assert.Equal(t, util.CodeRequestCancelled, apiErr.Code, err.Error())
+		assert.Equal(t, util.StatusClientClosedRequest, apiErr.Status)
+		assert.Less(t, apiErr.Status, http.StatusInternalServerError)
+		assert.ErrorIs(t, apiErr, err, "the cause stays in the chain for the logs")
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"apigateway/core/util"
+	"pkg/logger"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func AccessLog(log *zap.Logger) gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+		context.Next()
+
+		status := context.Writer.Status()
+		fields := []zap.Field{
+			zap.String("method", context.Request.Method),
+			zap.String("route", context.FullPath()),
+			zap.String("path", context.Request.URL.Path),
+			zap.Int("status", status),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("bytes", context.Writer.Size()),
+			zap.String("client_ip", context.ClientIP()),
+			zap.String("user_agent", context.Request.UserAgent()),
+		}
+
+		if len(context.Errors) > 0 {
+			fields = append(fields, zap.String("errors", context.Errors.String()))
+		}
+
+		requestLog := logger.WithContext(context.Request.Context(), log)
+
+		switch {
+		case status >= 500:
+			requestLog.Error("request", fields...)
+		case status >= 400:
+			requestLog.Warn("request", fields...)
+		default:
+			requestLog.Info("request", fields...)
+		}
+	}
+}
+
+func Recovery(log *zap.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(context *gin.Context, recovered any) {
+		logger.WithContext(context.Request.Context(), log).
+			Error("panic recovered", zap.Any("panic", recovered), zap.Stack("stacktrace"))
+		util.SendProblem(context, util.NewAPIError(util.CodeInternal, ""))
+		context.Abort()
+	})
+}
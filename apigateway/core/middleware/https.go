@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HSTS tells browsers to stick to HTTPS for maxAge. The header is only
+// sent on TLS connections; the plain listener may share the router.
+func HSTS(maxAge time.Duration) gin.HandlerFunc {
+	value := fmt.Sprintf("max-age=%d; includeSubDomains", int(maxAge.Seconds()))
+
+	return func(context *gin.Context) {
+		if context.Request.TLS != nil {
+			context.Header("Strict-Transport-Security", value)
+		}
+		context.Next()
+	}
+}
+
+// probePaths stay on plain HTTP so container and orchestrator probes,
+// which don't follow redirects to a certificate they can't verify, keep
+// working when the redirect is on.
+var probePaths = map[string]bool{"/livez": true, "/readyz": true}
+
+// RedirectToHTTPS answers plain HTTP requests with a permanent redirect to
+// the same path on the HTTPS port. Health probes are served by next.
+func RedirectToHTTPS(httpsPort string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if probePaths[request.URL.Path] {
+			next.ServeHTTP(writer, request)
+			return
+		}
+
+		host := request.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+
+		target := fmt.Sprintf("https://%s%s", host, request.URL.RequestURI())
+		http.Redirect(writer, request, target, http.StatusPermanentRedirect)
+	})
+}
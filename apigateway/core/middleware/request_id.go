@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"context"
+	"pkg/requestid"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestID accepts the caller's X-Request-ID when it is well formed and
+// generates one otherwise, then echoes it back and keeps it in the request
+// context for the logger, the error envelope and the gRPC client.
+func RequestID() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		id := context.GetHeader(requestid.Header)
+		if !requestid.IsValid(id) {
+			id = requestid.Generate()
+		}
+
+		context.Request = context.Request.WithContext(
+			requestid.NewContext(context.Request.Context(), id),
+		)
+		context.Header(requestid.Header, id)
+
+		context.Next()
+	}
+}
+
+func RequestIDClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if id := requestid.FromContext(ctx); id != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, requestid.MetadataKey, id)
+		}
+
+		return invoker(ctx, method, req, reply, conn, opts...)
+	}
+}
@@ -12,11 +12,14 @@ func Register(
 	router *gin.Engine,
 	moviesUsecase *usecases.MoviesUsecases,
 	logger *zap.Logger,
+	healthHandler *handler.HealthHandler,
 ) {
+	handler.RegisterHealthRoutes(router, healthHandler)
+
 	api := router.Group("/v1")
 	{
+		api.GET("/health", healthHandler.LegacyHealth) // Deprecated alias of /readyz
 		handler.RegisterMoviesRoutes(api, moviesUsecase, logger)
-		handler.RegisterHealthRoute(api)
 		handler.RegisterSwagger(api)
 	}
 }
@@ -2,27 +2,27 @@ package usecases
 
 import (
 	"apigateway/core/domain"
-	"apigateway/core/proto"
 	"apigateway/core/util"
-	"apigateway/infra/clients"
 	"context"
 
 	"go.uber.org/zap"
 )
 
 type MoviesUsecases struct {
-	Client *clients.MoviesGRPCClient
+	Client MoviesClient
 	Logger *zap.Logger
 }
 
+// MoviesClient is the outbound port to the movies service. Adapters live
+// in infra/clients and must return errors util.ToAPIError can map.
 type MoviesClient interface {
-	GetMovie(ctx context.Context, id int) (*domain.Movie, error)
-	GetMovies(ctx context.Context, pageNumber, resultsPerPage int) ([]*domain.Movie, error)
-	CreateMovie(ctx context.Context, movie *domain.Movie) (domain.Movie, error)
-	DeleteMovie(ctx context.Context, id int) error
+	GetMovie(ctx context.Context, id uint32) (*domain.Movie, error)
+	GetMovies(ctx context.Context, page, limit uint32) (*domain.MovieList, error)
+	CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error)
+	DeleteMovie(ctx context.Context, id uint32) error
 }
 
-func NewMoviesUseCases(client *clients.MoviesGRPCClient, logger *zap.Logger) *MoviesUsecases {
+func NewMoviesUseCases(client MoviesClient, logger *zap.Logger) *MoviesUsecases {
 	return &MoviesUsecases{
 		Client: client,
 		Logger: logger,
@@ -30,14 +30,7 @@ func NewMoviesUseCases(client *clients.MoviesGRPCClient, logger *zap.Logger) *Mo
 }
 
 func (m *MoviesUsecases) GetMovie(ctx context.Context, id int) (*domain.Movie, error) {
-	movieQuery, err := m.Client.GetMovie(ctx, uint32(id))
-
-	if err != nil {
-		return nil, err
-	}
-
-	movie := domain.ParseMovie(movieQuery)
-	return movie, err
+	return m.Client.GetMovie(ctx, uint32(id))
 }
 
 func (m *MoviesUsecases) GetMovies(ctx context.Context, pageNumber, resultsPerPage int) (*domain.MovieList, error) {
@@ -53,44 +46,29 @@ func (m *MoviesUsecases) GetMovies(ctx context.Context, pageNumber, resultsPerPa
 		return nil, err
 	}
 
-	movieList, err := m.Client.GetMovies(ctx, pageNumber, resultsPerPage)
+	movieList, err := m.Client.GetMovies(ctx, uint32(pageNumber), uint32(resultsPerPage))
 
 	if err != nil {
 		return nil, err
 	}
 
-	movies := domain.ParseMovies(movieList.Movies)
-	if movies == nil {
+	if len(movieList.Movies) == 0 {
 		return nil, util.ErrMoviePageNotFound
 	}
 
-	hasMore := domain.HasMore(movieList.Total, movieList.Page, uint32(resultsPerPage))
-	return &domain.MovieList{
-		Movies:  movies,
-		More:    hasMore,
-		Total:   movieList.Total,
-		Page:    movieList.Page,
-		Results: uint32(resultsPerPage),
-	}, err
+	movieList.More = domain.HasMore(movieList.Total, movieList.Page, uint32(resultsPerPage))
+	movieList.Results = uint32(resultsPerPage)
+	return movieList, nil
 }
 
-func (m *MoviesUsecases) CreateMovie(ctx context.Context, movie *proto.Movie) (*domain.Movie, error) {
-
-	movieQuery, err := m.Client.CreateMovie(ctx, movie)
-
-	if err != nil {
-		return nil, err
-	}
-
-	parsedMovie := domain.ParseMovie(movieQuery)
-
-	if err := domain.IsValidMovie(parsedMovie); err != nil {
+func (m *MoviesUsecases) CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error) {
+	if err := domain.IsValidMovie(movie); err != nil {
 		return nil, err
 	}
 
-	return parsedMovie, nil
+	return m.Client.CreateMovie(ctx, movie)
 }
 
 func (m *MoviesUsecases) DeleteMovie(ctx context.Context, id int) error {
-	return m.Client.DeleteMovie(ctx, id)
+	return m.Client.DeleteMovie(ctx, uint32(id))
 }
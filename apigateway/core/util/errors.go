@@ -21,17 +21,3 @@ var ErrMovieNotFound = errors.New(movieNotFound)
 var ErrMoviePageNotFound = errors.New(moviePageNotFound)
 var ErrTitleEmpty = errors.New(titleEmpty)
 var ErrYearEmpty = errors.New(yearEmpty)
-
-func IsErrInvalidParams(err error) bool {
-	if err == ErrPageNumberInvalid || err == ErrPageSizeShort || err == ErrPageSizeLong {
-		return true
-	}
-	return false
-}
-
-func IsInvalidBody(err error) bool {
-	if err == ErrTitleEmpty || err == ErrYearEmpty {
-		return true
-	}
-	return false
-}
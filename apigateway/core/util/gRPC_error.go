@@ -1,15 +1,29 @@
 package util
 
 import (
+	"fmt"
+	"time"
+
 	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type GRPCError struct {
-	Code    codes.Code `json:"code"`
-	Message string     `json:"message"`
-	Details []any      `json:"details,omitempty"`
+	Code       codes.Code       `json:"code"`
+	Message    string           `json:"message"`
+	Reason     string           `json:"reason,omitempty"`
+	Details    []any            `json:"details,omitempty"`
+	Violations []FieldViolation `json:"violations,omitempty"`
+	Resource   *ResourceRef     `json:"resource,omitempty"`
+	RetryAfter time.Duration    `json:"retry_after,omitempty"`
+	IsStatus   bool             `json:"-"`
+}
+
+type ResourceRef struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
 }
 
 func ParseGRPCError(err error) *GRPCError {
@@ -25,10 +39,69 @@ func ParseGRPCError(err error) *GRPCError {
 		}
 	}
 
-	return &GRPCError{
-		Code:    st.Code(),
-		Message: st.Message(),
-		Details: st.Details(),
+	grpcErr := &GRPCError{
+		Code:     st.Code(),
+		Message:  st.Message(),
+		Details:  st.Details(),
+		IsStatus: true,
+	}
+
+	for _, detail := range grpcErr.Details {
+		switch detail := detail.(type) {
+		case *errdetails.ErrorInfo:
+			grpcErr.Reason = detail.Reason
+		case *errdetails.BadRequest:
+			for _, violation := range detail.FieldViolations {
+				grpcErr.Violations = append(grpcErr.Violations, FieldViolation{
+					Field:       violation.Field,
+					Description: violation.Description,
+				})
+			}
+		case *errdetails.ResourceInfo:
+			grpcErr.Resource = &ResourceRef{Type: detail.ResourceType, Name: detail.ResourceName}
+		case *errdetails.RetryInfo:
+			grpcErr.RetryAfter = detail.RetryDelay.AsDuration()
+		}
+	}
+
+	return grpcErr
+}
+
+// APIError prefers the stable reason sent by the movies service and falls
+// back to the status code. The upstream message is never exposed; only
+// the structured details (violations, resource, retry hint) are.
+func (grpcErr *GRPCError) APIError() *APIError {
+	apiErr := NewAPIError(grpcErr.errorCode(), "")
+	apiErr.Violations = grpcErr.Violations
+	apiErr.RetryAfter = grpcErr.RetryAfter
+
+	if resource := grpcErr.Resource; resource != nil && resource.Name != "" {
+		apiErr.Detail = fmt.Sprintf("%s %s: %s", resource.Type, resource.Name, apiErr.Title)
+	}
+
+	return apiErr
+}
+
+func (grpcErr *GRPCError) errorCode() ErrorCode {
+	if code := ErrorCode(grpcErr.Reason); code != "" && IsKnownCode(code) {
+		return code
+	}
+
+	switch grpcErr.Code {
+	case codes.NotFound:
+		return CodeMovieNotFound
+	case codes.InvalidArgument:
+		return CodeValidationFailed
+	case codes.AlreadyExists:
+		return CodeMovieConflict
+	case codes.Unavailable:
+		return CodeServiceUnavailable
+	case codes.DeadlineExceeded:
+		return CodeUpstreamTimeout
+	case codes.Canceled:
+		return CodeRequestCancelled
+	default:
+		return CodeInternal
 	}
 }
 
@@ -0,0 +1,157 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrorCode is the stable, machine readable identifier of an error. Codes
+// are part of the public contract: add new ones, never rename them. The
+// movies service sends the same codes as the ErrorInfo reason.
+type ErrorCode string
+
+const (
+	CodeInvalidPageNumber  ErrorCode = "INVALID_PAGE_NUMBER"
+	CodeInvalidPageSize    ErrorCode = "INVALID_PAGE_SIZE"
+	CodeInvalidID          ErrorCode = "INVALID_ID"
+	CodeInvalidRequest     ErrorCode = "INVALID_REQUEST"
+	CodeValidationFailed   ErrorCode = "VALIDATION_FAILED"
+	CodeMovieNotFound      ErrorCode = "MOVIE_NOT_FOUND"
+	CodePageNotFound       ErrorCode = "PAGE_NOT_FOUND"
+	CodeMovieConflict      ErrorCode = "MOVIE_CONFLICT"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+	CodeUpstreamTimeout    ErrorCode = "UPSTREAM_TIMEOUT"
+	CodeRequestCancelled   ErrorCode = "REQUEST_CANCELLED"
+	CodeInternal           ErrorCode = "INTERNAL_ERROR"
+)
+
+// StatusClientClosedRequest is the non-standard status nginx uses when the
+// client goes away before the answer; the body is rarely read, but logs
+// and metrics no longer count it as a server error.
+const StatusClientClosedRequest = 499
+
+type catalogueEntry struct {
+	status int
+	title  string
+}
+
+var catalogue = map[ErrorCode]catalogueEntry{
+	CodeInvalidPageNumber:  {http.StatusBadRequest, "Invalid `pageNumber` value"},
+	CodeInvalidPageSize:    {http.StatusBadRequest, "Invalid `resultsPerPage` value"},
+	CodeInvalidID:          {http.StatusBadRequest, "Invalid `id` value"},
+	CodeInvalidRequest:     {http.StatusBadRequest, "Invalid request"},
+	CodeValidationFailed:   {http.StatusBadRequest, "Validation failed"},
+	CodeMovieNotFound:      {http.StatusNotFound, "movie not found"},
+	CodePageNotFound:       {http.StatusNotFound, "Page not found"},
+	CodeMovieConflict:      {http.StatusConflict, "movie already exists"},
+	CodeServiceUnavailable: {http.StatusServiceUnavailable, "movies service unavailable"},
+	CodeUpstreamTimeout:    {http.StatusGatewayTimeout, "movies service timed out"},
+	CodeRequestCancelled:   {StatusClientClosedRequest, "request cancelled by the client"},
+	CodeInternal:           {http.StatusInternalServerError, "internal server error"},
+}
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// APIError is what handlers send back. Detail must be safe to show to
+// clients; internal causes stay in Cause and only reach the logs.
+type APIError struct {
+	Code       ErrorCode
+	Status     int
+	Title      string
+	Detail     string
+	Violations []FieldViolation
+	RetryAfter time.Duration
+	Cause      error
+}
+
+func (apiErr *APIError) Error() string {
+	if apiErr.Detail != "" {
+		return apiErr.Detail
+	}
+	return apiErr.Title
+}
+
+func (apiErr *APIError) Unwrap() error {
+	return apiErr.Cause
+}
+
+func (apiErr *APIError) ProblemType() string {
+	return "urn:apigateway:problem:" + strings.ToLower(strings.ReplaceAll(string(apiErr.Code), "_", "-"))
+}
+
+func NewAPIError(code ErrorCode, detail string) *APIError {
+	entry, ok := catalogue[code]
+	if !ok {
+		code, entry = CodeInternal, catalogue[CodeInternal]
+	}
+
+	return &APIError{
+		Code:   code,
+		Status: entry.status,
+		Title:  entry.title,
+		Detail: detail,
+	}
+}
+
+func NewFieldError(code ErrorCode, field, description string) *APIError {
+	apiErr := NewAPIError(code, description)
+	apiErr.Violations = []FieldViolation{{Field: field, Description: description}}
+	return apiErr
+}
+
+func NewValidationError(violations ...FieldViolation) *APIError {
+	apiErr := NewAPIError(CodeValidationFailed, "")
+	apiErr.Violations = violations
+	return apiErr
+}
+
+func IsKnownCode(code ErrorCode) bool {
+	_, ok := catalogue[code]
+	return ok
+}
+
+// ToAPIError maps anything a usecase can return into the catalogue.
+// Unknown errors become INTERNAL_ERROR without leaking their message.
+func ToAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	switch err {
+	case ErrPageNumberInvalid:
+		return NewFieldError(CodeInvalidPageNumber, "pageNumber", err.Error())
+	case ErrPageSizeShort, ErrPageSizeLong:
+		return NewFieldError(CodeInvalidPageSize, "resultsPerPage", err.Error())
+	case ErrMoviePageNotFound:
+		return NewAPIError(CodePageNotFound, err.Error())
+	case ErrMovieNotFound:
+		return NewAPIError(CodeMovieNotFound, err.Error())
+	}
+
+	if errors.Is(err, context.Canceled) {
+		apiErr = NewAPIError(CodeRequestCancelled, "")
+		apiErr.Cause = err
+		return apiErr
+	}
+
+	if grpcErr := ParseGRPCError(err); grpcErr.IsStatus {
+		apiErr = grpcErr.APIError()
+		apiErr.Cause = err
+		return apiErr
+	}
+
+	apiErr = NewAPIError(CodeInternal, "")
+	apiErr.Cause = err
+	return apiErr
+}
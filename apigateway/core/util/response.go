@@ -1,6 +1,17 @@
 package util
 
-import "github.com/gin-gonic/gin"
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"pkg/requestid"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const problemContentType = "application/problem+json"
 
 func SendSuccess(context *gin.Context, status int, data interface{}) {
 	context.JSON(status, gin.H{
@@ -9,12 +20,67 @@ func SendSuccess(context *gin.Context, status int, data interface{}) {
 	})
 }
 
-func SendError(context *gin.Context, status int, message string, err error) {
-	context.JSON(status, gin.H{
+// SendProblem renders an RFC 7807 document when the client asks for
+// application/problem+json and the usual envelope otherwise. Both carry
+// the same stable code, request ID and field violations.
+func SendProblem(context *gin.Context, apiErr *APIError) {
+	requestID := requestid.FromContext(context.Request.Context())
+
+	if apiErr.RetryAfter > 0 {
+		seconds := int(math.Ceil(apiErr.RetryAfter.Seconds()))
+		context.Header("Retry-After", strconv.Itoa(seconds))
+	}
+
+	if strings.Contains(context.GetHeader("Accept"), problemContentType) {
+		problem := gin.H{
+			"type":       apiErr.ProblemType(),
+			"title":      apiErr.Title,
+			"status":     apiErr.Status,
+			"instance":   context.Request.URL.Path,
+			"code":       apiErr.Code,
+			"request_id": requestID,
+		}
+
+		if apiErr.Detail != "" {
+			problem["detail"] = apiErr.Detail
+		}
+
+		if len(apiErr.Violations) > 0 {
+			problem["violations"] = apiErr.Violations
+		}
+
+		context.Render(apiErr.Status, problemJSON{data: problem})
+		return
+	}
+
+	body := gin.H{
+		"code":       apiErr.Code,
+		"message":    apiErr.Title,
+		"details":    apiErr.Error(),
+		"request_id": requestID,
+	}
+
+	if len(apiErr.Violations) > 0 {
+		body["violations"] = apiErr.Violations
+	}
+
+	context.JSON(apiErr.Status, gin.H{
 		"success": false,
-		"error": gin.H{
-			"message": message,
-			"details": err.Error(),
-		},
+		"error":   body,
 	})
 }
+
+// problemJSON is a gin render that keeps the problem+json content type
+// instead of the application/json one gin.JSON forces.
+type problemJSON struct {
+	data any
+}
+
+func (render problemJSON) Render(writer http.ResponseWriter) error {
+	render.WriteContentType(writer)
+	return json.NewEncoder(writer).Encode(render.data)
+}
+
+func (render problemJSON) WriteContentType(writer http.ResponseWriter) {
+	writer.Header().Set("Content-Type", problemContentType)
+}
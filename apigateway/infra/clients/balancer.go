@@ -0,0 +1,284 @@
+package clients
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/balancer"
+	"google.golang.org/grpc/balancer/base"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/serviceconfig"
+)
+
+const (
+	balancerName = "movies_balancer"
+
+	PolicyRoundRobin   = "round_robin"
+	PolicyLeastRequest = "least_request"
+
+	maxEjectionMultiplier = 10
+)
+
+func init() {
+	balancer.Register(balancerBuilder{})
+}
+
+type balancerConfig struct {
+	serviceconfig.LoadBalancingConfig `json:"-"`
+
+	Policy              string `json:"policy"`
+	ConsecutiveFailures int    `json:"consecutiveFailures"`
+	BaseEjectionTime    string `json:"baseEjectionTime"`
+	MaxEjectionPercent  int    `json:"maxEjectionPercent"`
+
+	baseEjectionTime time.Duration
+}
+
+// balancerBuilder wraps the stock base balancer, which keeps one SubConn
+// per resolved address and only hands READY (and, with health checking,
+// SERVING) ones to the picker. The picker adds round-robin or
+// least-request selection on top, skipping endpoints ejected as outliers.
+type balancerBuilder struct{}
+
+func (balancerBuilder) Name() string {
+	return balancerName
+}
+
+func (balancerBuilder) Build(cc balancer.ClientConn, opts balancer.BuildOptions) balancer.Balancer {
+	picker := &pickerBuilder{detector: newOutlierDetector()}
+	return &moviesBalancer{
+		Balancer: base.NewBalancerBuilder(balancerName, picker, base.Config{HealthCheck: true}).Build(cc, opts),
+		picker:   picker,
+	}
+}
+
+func (balancerBuilder) ParseConfig(raw json.RawMessage) (serviceconfig.LoadBalancingConfig, error) {
+	cfg := &balancerConfig{
+		Policy:              PolicyRoundRobin,
+		ConsecutiveFailures: 5,
+		BaseEjectionTime:    "30s",
+		MaxEjectionPercent:  50,
+	}
+
+	if err := json.Unmarshal(raw, cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Policy != PolicyRoundRobin && cfg.Policy != PolicyLeastRequest {
+		return nil, fmt.Errorf("unknown load balancing policy %q", cfg.Policy)
+	}
+
+	duration, err := time.ParseDuration(cfg.BaseEjectionTime)
+	if err != nil {
+		return nil, err
+	}
+	cfg.baseEjectionTime = duration
+
+	return cfg, nil
+}
+
+type moviesBalancer struct {
+	balancer.Balancer
+	picker *pickerBuilder
+}
+
+func (moviesBalancer *moviesBalancer) UpdateClientConnState(state balancer.ClientConnState) error {
+	if cfg, ok := state.BalancerConfig.(*balancerConfig); ok {
+		moviesBalancer.picker.detector.setConfig(cfg)
+	}
+	moviesBalancer.picker.detector.prune(state.ResolverState.Addresses)
+
+	return moviesBalancer.Balancer.UpdateClientConnState(state)
+}
+
+type pickerBuilder struct {
+	detector *outlierDetector
+}
+
+func (builder *pickerBuilder) Build(info base.PickerBuildInfo) balancer.Picker {
+	if len(info.ReadySCs) == 0 {
+		return base.NewErrPicker(balancer.ErrNoSubConnAvailable)
+	}
+
+	picker := &picker{detector: builder.detector}
+	for subConn, subConnInfo := range info.ReadySCs {
+		picker.subConns = append(picker.subConns, subConn)
+		picker.addresses = append(picker.addresses, subConnInfo.Address.Addr)
+	}
+
+	return picker
+}
+
+type picker struct {
+	subConns  []balancer.SubConn
+	addresses []string
+	detector  *outlierDetector
+	next      atomic.Uint32
+}
+
+func (picker *picker) Pick(balancer.PickInfo) (balancer.PickResult, error) {
+	candidates := picker.detector.available(picker.addresses)
+
+	var chosen int
+	if picker.detector.policy() == PolicyLeastRequest && len(candidates) > 1 {
+		first := candidates[rand.IntN(len(candidates))]
+		second := candidates[rand.IntN(len(candidates))]
+		chosen = first
+		if picker.detector.inFlight(picker.addresses[second]) < picker.detector.inFlight(picker.addresses[first]) {
+			chosen = second
+		}
+	} else {
+		chosen = candidates[int(picker.next.Add(1)-1)%len(candidates)]
+	}
+
+	address := picker.addresses[chosen]
+	picker.detector.started(address)
+
+	return balancer.PickResult{
+		SubConn: picker.subConns[chosen],
+		Done: func(done balancer.DoneInfo) {
+			picker.detector.finished(address, done.Err, len(picker.addresses))
+		},
+	}, nil
+}
+
+type endpointStats struct {
+	inFlight            int64
+	consecutiveFailures int
+	ejections           int
+	ejectedUntil        time.Time
+}
+
+// outlierDetector ejects an endpoint for a while after too many
+// consecutive backend failures, growing the ejection time each time it
+// happens again. It is shared by every picker the balancer builds so the
+// state survives SubConn churn.
+type outlierDetector struct {
+	mutex sync.Mutex
+	cfg   *balancerConfig
+	stats map[string]*endpointStats
+}
+
+func newOutlierDetector() *outlierDetector {
+	cfg, _ := balancerBuilder{}.ParseConfig([]byte("{}"))
+	return &outlierDetector{
+		cfg:   cfg.(*balancerConfig),
+		stats: make(map[string]*endpointStats),
+	}
+}
+
+func (detector *outlierDetector) setConfig(cfg *balancerConfig) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+	detector.cfg = cfg
+}
+
+func (detector *outlierDetector) policy() string {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+	return detector.cfg.Policy
+}
+
+// prune forgets endpoints the resolver no longer returns, so replicas
+// that went away don't count against MaxEjectionPercent. Ones with calls
+// still in flight are kept until the next update.
+func (detector *outlierDetector) prune(addresses []resolver.Address) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	current := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		current[address.Addr] = true
+	}
+
+	for address, stats := range detector.stats {
+		if !current[address] && stats.inFlight <= 0 {
+			delete(detector.stats, address)
+		}
+	}
+}
+
+func (detector *outlierDetector) statsFor(address string) *endpointStats {
+	stats, ok := detector.stats[address]
+	if !ok {
+		stats = &endpointStats{}
+		detector.stats[address] = stats
+	}
+	return stats
+}
+
+// available returns the indexes of non-ejected addresses, or all of them
+// when everything is ejected so we still make progress.
+func (detector *outlierDetector) available(addresses []string) []int {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	now := time.Now()
+	candidates := make([]int, 0, len(addresses))
+	for index, address := range addresses {
+		if detector.statsFor(address).ejectedUntil.Before(now) {
+			candidates = append(candidates, index)
+		}
+	}
+
+	if len(candidates) == 0 {
+		for index := range addresses {
+			candidates = append(candidates, index)
+		}
+	}
+
+	return candidates
+}
+
+func (detector *outlierDetector) inFlight(address string) int64 {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+	return detector.statsFor(address).inFlight
+}
+
+func (detector *outlierDetector) started(address string) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+	detector.statsFor(address).inFlight++
+}
+
+func (detector *outlierDetector) finished(address string, err error, total int) {
+	detector.mutex.Lock()
+	defer detector.mutex.Unlock()
+
+	stats := detector.statsFor(address)
+	stats.inFlight--
+
+	now := time.Now()
+	if !isBackendFailure(err) {
+		stats.consecutiveFailures = 0
+		if stats.ejections > 0 && stats.ejectedUntil.Before(now) {
+			stats.ejections--
+		}
+		return
+	}
+
+	stats.consecutiveFailures++
+	if stats.consecutiveFailures < detector.cfg.ConsecutiveFailures {
+		return
+	}
+
+	ejected := 0
+	for _, other := range detector.stats {
+		if other.ejectedUntil.After(now) {
+			ejected++
+		}
+	}
+
+	if (ejected+1)*100 > total*detector.cfg.MaxEjectionPercent {
+		return
+	}
+
+	stats.ejections = min(stats.ejections+1, maxEjectionMultiplier)
+	stats.ejectedUntil = now.Add(time.Duration(stats.ejections) * detector.cfg.baseEjectionTime)
+	stats.consecutiveFailures = 0
+}
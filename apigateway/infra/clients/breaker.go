@@ -0,0 +1,123 @@
+package clients
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+const (
+	circuitOpenMessage = "movies service unavailable: circuit breaker open"
+	healthMethodPrefix = "/grpc.health.v1.Health/"
+)
+
+// CircuitBreaker stops calling the movies service after a run of
+// consecutive transport failures and fails fast with Unavailable until
+// OpenTimeout has passed, then lets a few probe calls through.
+type CircuitBreaker struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenMaxCalls int
+
+	mutex    sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration, halfOpenMaxCalls int) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		OpenTimeout:      openTimeout,
+		HalfOpenMaxCalls: halfOpenMaxCalls,
+	}
+}
+
+func (breaker *CircuitBreaker) allow() bool {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	switch breaker.state {
+	case breakerOpen:
+		if time.Since(breaker.openedAt) < breaker.OpenTimeout {
+			return false
+		}
+		breaker.state = breakerHalfOpen
+		breaker.probes = 0
+		fallthrough
+	case breakerHalfOpen:
+		if breaker.probes >= breaker.HalfOpenMaxCalls {
+			return false
+		}
+		breaker.probes++
+	}
+
+	return true
+}
+
+func (breaker *CircuitBreaker) record(err error) {
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+
+	if !isBackendFailure(err) {
+		breaker.state = breakerClosed
+		breaker.failures = 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.state == breakerHalfOpen || breaker.failures >= breaker.FailureThreshold {
+		breaker.state = breakerOpen
+		breaker.openedAt = time.Now()
+	}
+}
+
+func (breaker *CircuitBreaker) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		// Health checks must see the backend as it is, and must not use
+		// up the half-open slots meant for real calls.
+		if strings.HasPrefix(method, healthMethodPrefix) {
+			return invoker(ctx, method, req, reply, conn, opts...)
+		}
+
+		if !breaker.allow() {
+			return status.Error(codes.Unavailable, circuitOpenMessage)
+		}
+
+		err := invoker(ctx, method, req, reply, conn, opts...)
+		breaker.record(err)
+		return err
+	}
+}
+
+// isBackendFailure only counts errors that say the backend is unhealthy;
+// NotFound or InvalidArgument are normal answers and must not trip it.
+func isBackendFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Unknown:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,130 @@
+// Package fake provides an in-memory movies service for tests and local
+// runs of the gateway without a movies backend.
+package fake
+
+import (
+	"apigateway/core/domain"
+	"apigateway/core/usecases"
+	"apigateway/core/util"
+	"context"
+	"sort"
+	"sync"
+)
+
+var _ usecases.MoviesClient = (*MoviesClient)(nil)
+
+type MoviesClient struct {
+	mutex  sync.Mutex
+	movies map[uint32]*domain.Movie
+	nextID uint32
+	err    error
+}
+
+func NewMoviesClient() *MoviesClient {
+	return &MoviesClient{
+		movies: make(map[uint32]*domain.Movie),
+		nextID: 1,
+	}
+}
+
+// Seed stores movies as they are, keeping ID allocation past the highest one.
+func (c *MoviesClient) Seed(movies ...*domain.Movie) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for _, movie := range movies {
+		stored := *movie
+		c.movies[movie.Id] = &stored
+		if movie.Id >= c.nextID {
+			c.nextID = movie.Id + 1
+		}
+	}
+}
+
+// FailWith makes every following call return err until it is called
+// again with nil, to simulate an unhealthy movies service.
+func (c *MoviesClient) FailWith(err error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.err = err
+}
+
+func (c *MoviesClient) GetMovie(ctx context.Context, id uint32) (*domain.Movie, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	movie, ok := c.movies[id]
+	if !ok {
+		return nil, util.ErrMovieNotFound
+	}
+
+	found := *movie
+	return &found, nil
+}
+
+// GetMovies pages newest first, like the movies service does.
+func (c *MoviesClient) GetMovies(ctx context.Context, page, limit uint32) (*domain.MovieList, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	movies := make([]*domain.Movie, 0, len(c.movies))
+	for _, movie := range c.movies {
+		found := *movie
+		movies = append(movies, &found)
+	}
+
+	sort.Slice(movies, func(i, j int) bool {
+		return movies[i].Id > movies[j].Id
+	})
+
+	total := uint32(len(movies))
+	start := min((page-1)*limit, total)
+	end := min(start+limit, total)
+
+	return &domain.MovieList{
+		Movies: movies[start:end],
+		Page:   page,
+		Total:  total,
+	}, nil
+}
+
+func (c *MoviesClient) CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	created := &domain.Movie{Id: c.nextID, Title: movie.Title, Year: movie.Year}
+	c.movies[created.Id] = created
+	c.nextID++
+
+	result := *created
+	return &result, nil
+}
+
+func (c *MoviesClient) DeleteMovie(ctx context.Context, id uint32) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	if _, ok := c.movies[id]; !ok {
+		return util.ErrMovieNotFound
+	}
+
+	delete(c.movies, id)
+	return nil
+}
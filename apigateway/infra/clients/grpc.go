@@ -2,20 +2,80 @@ package clients
 
 import (
 	"apigateway/core/config"
-	"apigateway/core/proto"
+	"apigateway/core/domain"
+	"apigateway/core/middleware"
+	"apigateway/core/usecases"
+	"apigateway/pkg/metrics"
 	"context"
+	"fmt"
+	"pkg/tlsreload"
+	"proto"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	_ "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+var _ usecases.MoviesClient = (*MoviesGRPCClient)(nil)
+
 type MoviesGRPCClient struct {
 	Conn   *grpc.ClientConn
 	Client proto.MovieServiceClient
+	Health healthpb.HealthClient
+
+	stopWatch context.CancelFunc
 }
 
-func NewGrpcClient(cfg *config.Config) (*MoviesGRPCClient, error) {
-	conn, err := grpc.Dial(cfg.GrpcServerAddress, grpc.WithInsecure())
+// NewGrpcClient dials the movies service through the polling resolver.
+// Extra dial options are appended last, e.g. a custom dialer in tests.
+func NewGrpcClient(cfg *config.Config, log *zap.Logger, opts ...grpc.DialOption) (*MoviesGRPCClient, error) {
+	serviceConfig, err := buildServiceConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	breaker := NewCircuitBreaker(
+		cfg.BreakerFailureThreshold,
+		cfg.BreakerOpenTimeout,
+		cfg.BreakerHalfOpenMaxCalls,
+	)
+
+	resolverBuilder := newPollingResolverBuilder(cfg.GrpcServerAddress, cfg.GrpcResolveInterval)
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	transportCredentials := insecure.NewCredentials()
+
+	if cfg.GrpcTLSCertFile != "" {
+		reloader, err := tlsreload.New(cfg.GrpcTLSCertFile, cfg.GrpcTLSKeyFile, cfg.GrpcTLSCAFile)
+		if err != nil {
+			stopWatch()
+			return nil, err
+		}
+
+		go reloader.Watch(watchCtx, cfg.TLSReloadInterval, log)
+		transportCredentials = credentials.NewTLS(reloader.ClientConfig(cfg.GrpcTLSServerName))
+		log.Info("mTLS enabled for gRPC client")
+	}
+
+	dialOptions := append([]grpc.DialOption{
+		grpc.WithResolvers(resolverBuilder),
+		grpc.WithTransportCredentials(transportCredentials),
+		grpc.WithDefaultServiceConfig(serviceConfig),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(
+			middleware.RequestIDClientInterceptor(),
+			breaker.UnaryClientInterceptor(),
+			metrics.UnaryClientInterceptor(),
+		),
+	}, opts...)
+
+	conn, err := grpc.NewClient(fmt.Sprintf("%s:///movies", resolverScheme), dialOptions...)
 	if err != nil {
+		stopWatch()
 		return nil, err
 	}
 
@@ -24,32 +84,57 @@ func NewGrpcClient(cfg *config.Config) (*MoviesGRPCClient, error) {
 	return &MoviesGRPCClient{
 		Conn:   conn,
 		Client: client,
+		Health: healthpb.NewHealthClient(conn),
+
+		stopWatch: stopWatch,
 	}, nil
 }
 
 func (c *MoviesGRPCClient) Close() error {
+	c.stopWatch()
 	return c.Conn.Close()
 }
 
-func (c *MoviesGRPCClient) GetMovie(ctx context.Context, id uint32) (*proto.Movie, error) {
+func (c *MoviesGRPCClient) Name() string {
+	return "movies"
+}
+
+// CheckHealth probes the movies service through the standard
+// grpc.health.v1 protocol, which in turn pings its database.
+func (c *MoviesGRPCClient) CheckHealth(ctx context.Context) error {
+	resp, err := c.Health.Check(ctx, &healthpb.HealthCheckRequest{
+		Service: proto.MovieService_ServiceDesc.ServiceName,
+	})
+	if err != nil {
+		return err
+	}
+
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("movies service is %s", resp.Status)
+	}
+
+	return nil
+}
+
+func (c *MoviesGRPCClient) GetMovie(ctx context.Context, id uint32) (*domain.Movie, error) {
 	resp, err := c.Client.GetMovie(ctx, &proto.MovieIdRequest{Id: id})
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	return domain.ParseMovie(resp), nil
 }
 
-func (c *MoviesGRPCClient) GetMovies(ctx context.Context, page, results int) (*proto.MovieListResponse, error) {
-	resp, err := c.Client.GetMovies(ctx, &proto.GetMoviesRequest{Page: uint32(page), Limit: uint32(results)})
+func (c *MoviesGRPCClient) GetMovies(ctx context.Context, page, limit uint32) (*domain.MovieList, error) {
+	resp, err := c.Client.GetMovies(ctx, &proto.GetMoviesRequest{Page: page, Limit: limit})
 	if err != nil {
 		return nil, err
 	}
 
-	return resp, nil
+	return domain.ParseMovieList(resp), nil
 }
 
-func (c *MoviesGRPCClient) CreateMovie(ctx context.Context, movie *proto.Movie) (*proto.Movie, error) {
+func (c *MoviesGRPCClient) CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error) {
 	resp, err := c.Client.CreateMovie(ctx, &proto.Movie{
 		Title: movie.Title,
 		Year:  movie.Year,
@@ -59,10 +144,10 @@ func (c *MoviesGRPCClient) CreateMovie(ctx context.Context, movie *proto.Movie)
 		return nil, err
 	}
 
-	return resp, nil
+	return domain.ParseMovie(resp), nil
 }
 
-func (c *MoviesGRPCClient) DeleteMovie(ctx context.Context, id int) error {
-	_, err := c.Client.DeleteMovie(ctx, &proto.MovieIdRequest{Id: uint32(id)})
+func (c *MoviesGRPCClient) DeleteMovie(ctx context.Context, id uint32) error {
+	_, err := c.Client.DeleteMovie(ctx, &proto.MovieIdRequest{Id: id})
 	return err
 }
@@ -0,0 +1,71 @@
+// Package recording captures the conversation between the gateway and the
+// movies service into a cassette file and plays it back later, so tests
+// can run against real responses without a movies backend.
+package recording
+
+import (
+	"apigateway/core/util"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Method   string          `json:"method"`
+	Request  json.RawMessage `json:"request"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *RecordedError  `json:"error,omitempty"`
+}
+
+// RecordedError keeps what the gateway exposes of an error, which is all a
+// replay needs to produce the same HTTP response.
+type RecordedError struct {
+	Code       util.ErrorCode        `json:"code"`
+	Detail     string                `json:"detail,omitempty"`
+	Violations []util.FieldViolation `json:"violations,omitempty"`
+	RetryAfter time.Duration         `json:"retry_after,omitempty"`
+}
+
+func newRecordedError(err error) *RecordedError {
+	apiErr := util.ToAPIError(err)
+	return &RecordedError{
+		Code:       apiErr.Code,
+		Detail:     apiErr.Detail,
+		Violations: apiErr.Violations,
+		RetryAfter: apiErr.RetryAfter,
+	}
+}
+
+func (recorded *RecordedError) apiError() *util.APIError {
+	apiErr := util.NewAPIError(recorded.Code, recorded.Detail)
+	apiErr.Violations = recorded.Violations
+	apiErr.RetryAfter = recorded.RetryAfter
+	return apiErr
+}
+
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cassette Cassette
+	if err := json.Unmarshal(data, &cassette); err != nil {
+		return nil, err
+	}
+
+	return &cassette, nil
+}
+
+func (cassette *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
@@ -0,0 +1,85 @@
+package recording
+
+import (
+	"apigateway/core/domain"
+	"apigateway/core/usecases"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+var _ usecases.MoviesClient = (*Recorder)(nil)
+
+type idRequest struct {
+	Id uint32 `json:"id"`
+}
+
+type pageRequest struct {
+	Page  uint32 `json:"page"`
+	Limit uint32 `json:"limit"`
+}
+
+// Recorder forwards every call to the wrapped client and keeps the
+// request, response and error in its cassette.
+type Recorder struct {
+	next     usecases.MoviesClient
+	mutex    sync.Mutex
+	cassette Cassette
+}
+
+func NewRecorder(next usecases.MoviesClient) *Recorder {
+	return &Recorder{next: next}
+}
+
+// Cassette returns a copy of everything recorded so far.
+func (recorder *Recorder) Cassette() *Cassette {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	interactions := append([]Interaction(nil), recorder.cassette.Interactions...)
+	return &Cassette{Interactions: interactions}
+}
+
+func (recorder *Recorder) Save(path string) error {
+	return recorder.Cassette().Save(path)
+}
+
+func (recorder *Recorder) GetMovie(ctx context.Context, id uint32) (*domain.Movie, error) {
+	movie, err := recorder.next.GetMovie(ctx, id)
+	recorder.record("GetMovie", idRequest{Id: id}, movie, err)
+	return movie, err
+}
+
+func (recorder *Recorder) GetMovies(ctx context.Context, page, limit uint32) (*domain.MovieList, error) {
+	list, err := recorder.next.GetMovies(ctx, page, limit)
+	recorder.record("GetMovies", pageRequest{Page: page, Limit: limit}, list, err)
+	return list, err
+}
+
+func (recorder *Recorder) CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error) {
+	created, err := recorder.next.CreateMovie(ctx, movie)
+	recorder.record("CreateMovie", movie, created, err)
+	return created, err
+}
+
+func (recorder *Recorder) DeleteMovie(ctx context.Context, id uint32) error {
+	err := recorder.next.DeleteMovie(ctx, id)
+	recorder.record("DeleteMovie", idRequest{Id: id}, nil, err)
+	return err
+}
+
+func (recorder *Recorder) record(method string, request, response any, err error) {
+	interaction := Interaction{Method: method}
+	interaction.Request, _ = json.Marshal(request)
+
+	if err != nil {
+		interaction.Error = newRecordedError(err)
+	} else if response != nil {
+		interaction.Response, _ = json.Marshal(response)
+	}
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	recorder.cassette.Interactions = append(recorder.cassette.Interactions, interaction)
+}
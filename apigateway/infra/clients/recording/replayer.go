@@ -0,0 +1,120 @@
+package recording
+
+import (
+	"apigateway/core/domain"
+	"apigateway/core/usecases"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var ErrNoInteraction = errors.New("no recorded interaction matches the call")
+
+var _ usecases.MoviesClient = (*Replayer)(nil)
+
+// Replayer answers calls from a cassette. Each interaction is used once,
+// in recording order, and only for a call with the same method and
+// request; anything else fails with ErrNoInteraction.
+type Replayer struct {
+	mutex        sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+func NewReplayerFromFile(path string) (*Replayer, error) {
+	cassette, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewReplayer(cassette), nil
+}
+
+// Remaining reports how many interactions have not been replayed yet.
+func (replayer *Replayer) Remaining() int {
+	replayer.mutex.Lock()
+	defer replayer.mutex.Unlock()
+
+	remaining := 0
+	for _, used := range replayer.used {
+		if !used {
+			remaining++
+		}
+	}
+	return remaining
+}
+
+func (replayer *Replayer) GetMovie(ctx context.Context, id uint32) (*domain.Movie, error) {
+	var movie domain.Movie
+	if err := replayer.replay("GetMovie", idRequest{Id: id}, &movie); err != nil {
+		return nil, err
+	}
+	return &movie, nil
+}
+
+func (replayer *Replayer) GetMovies(ctx context.Context, page, limit uint32) (*domain.MovieList, error) {
+	var list domain.MovieList
+	if err := replayer.replay("GetMovies", pageRequest{Page: page, Limit: limit}, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func (replayer *Replayer) CreateMovie(ctx context.Context, movie *domain.Movie) (*domain.Movie, error) {
+	var created domain.Movie
+	if err := replayer.replay("CreateMovie", movie, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (replayer *Replayer) DeleteMovie(ctx context.Context, id uint32) error {
+	return replayer.replay("DeleteMovie", idRequest{Id: id}, nil)
+}
+
+func (replayer *Replayer) replay(method string, request, response any) error {
+	encoded, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	replayer.mutex.Lock()
+	defer replayer.mutex.Unlock()
+
+	for i, interaction := range replayer.interactions {
+		if replayer.used[i] || interaction.Method != method || !sameJSON(interaction.Request, encoded) {
+			continue
+		}
+
+		replayer.used[i] = true
+		if interaction.Error != nil {
+			return interaction.Error.apiError()
+		}
+
+		if response != nil && len(interaction.Response) > 0 {
+			return json.Unmarshal(interaction.Response, response)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s %s", ErrNoInteraction, method, encoded)
+}
+
+// sameJSON ignores formatting, since saved cassettes are indented.
+func sameJSON(recorded, request []byte) bool {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, recorded); err != nil {
+		return false
+	}
+	return bytes.Equal(compacted.Bytes(), request)
+}
@@ -0,0 +1,126 @@
+package clients
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+)
+
+const resolverScheme = "movies"
+
+// pollingResolverBuilder resolves every configured host:port through DNS on
+// a fixed interval, so replicas added to or removed from the movies service
+// record are picked up without restarting the gateway. A plain list of
+// addresses works the same way since IPs resolve to themselves.
+type pollingResolverBuilder struct {
+	endpoints []string
+	interval  time.Duration
+}
+
+func newPollingResolverBuilder(addresses string, interval time.Duration) *pollingResolverBuilder {
+	var endpoints []string
+	for _, address := range strings.Split(addresses, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			endpoints = append(endpoints, address)
+		}
+	}
+
+	return &pollingResolverBuilder{endpoints: endpoints, interval: interval}
+}
+
+func (builder *pollingResolverBuilder) Scheme() string {
+	return resolverScheme
+}
+
+func (builder *pollingResolverBuilder) Build(
+	target resolver.Target,
+	cc resolver.ClientConn,
+	opts resolver.BuildOptions,
+) (resolver.Resolver, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	pollingResolver := &pollingResolver{
+		endpoints: builder.endpoints,
+		interval:  builder.interval,
+		cc:        cc,
+		trigger:   make(chan struct{}, 1),
+		cancel:    cancel,
+	}
+
+	pollingResolver.wg.Add(1)
+	go pollingResolver.watch(ctx)
+
+	return pollingResolver, nil
+}
+
+type pollingResolver struct {
+	endpoints []string
+	interval  time.Duration
+	cc        resolver.ClientConn
+	trigger   chan struct{}
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+func (pollingResolver *pollingResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case pollingResolver.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (pollingResolver *pollingResolver) Close() {
+	pollingResolver.cancel()
+	pollingResolver.wg.Wait()
+}
+
+func (pollingResolver *pollingResolver) watch(ctx context.Context) {
+	defer pollingResolver.wg.Done()
+
+	ticker := time.NewTicker(pollingResolver.interval)
+	defer ticker.Stop()
+
+	for {
+		pollingResolver.resolve(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-pollingResolver.trigger:
+		}
+	}
+}
+
+func (pollingResolver *pollingResolver) resolve(ctx context.Context) {
+	var addresses []resolver.Address
+	var lastErr error
+
+	for _, endpoint := range pollingResolver.endpoints {
+		host, port, err := net.SplitHostPort(endpoint)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		ips, err := net.DefaultResolver.LookupHost(ctx, host)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, ip := range ips {
+			addresses = append(addresses, resolver.Address{Addr: net.JoinHostPort(ip, port)})
+		}
+	}
+
+	if len(addresses) == 0 && lastErr != nil {
+		pollingResolver.cc.ReportError(lastErr)
+		return
+	}
+
+	_ = pollingResolver.cc.UpdateState(resolver.State{Addresses: addresses})
+}
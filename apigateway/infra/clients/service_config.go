@@ -0,0 +1,97 @@
+package clients
+
+import (
+	"apigateway/core/config"
+	"encoding/json"
+	"fmt"
+	"proto"
+	"time"
+)
+
+type methodName struct {
+	Service string `json:"service"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+	Name        []methodName `json:"name"`
+	Timeout     string       `json:"timeout,omitempty"`
+	RetryPolicy *retryPolicy `json:"retryPolicy,omitempty"`
+}
+
+type healthCheckConfig struct {
+	ServiceName string `json:"serviceName"`
+}
+
+type serviceConfig struct {
+	LoadBalancingConfig []map[string]balancerConfig `json:"loadBalancingConfig"`
+	HealthCheckConfig   healthCheckConfig           `json:"healthCheckConfig"`
+	MethodConfig        []methodConfig              `json:"methodConfig"`
+}
+
+// buildServiceConfig selects our balancer with per-endpoint health checks,
+// sets a deadline on every RPC and a retry policy on the idempotent reads
+// only. gRPC randomises each backoff between zero and the computed value,
+// which gives us jitter for free.
+func buildServiceConfig(cfg *config.Config) (string, error) {
+	service := proto.MovieService_ServiceDesc.ServiceName
+
+	reads := methodConfig{
+		Name: []methodName{
+			{Service: service, Method: "GetMovie"},
+			{Service: service, Method: "GetMovies"},
+		},
+		Timeout: durationJSON(cfg.GrpcReadTimeout),
+		RetryPolicy: &retryPolicy{
+			MaxAttempts:          cfg.GrpcRetryMaxAttempts,
+			InitialBackoff:       durationJSON(cfg.GrpcRetryInitialBackoff),
+			MaxBackoff:           durationJSON(cfg.GrpcRetryMaxBackoff),
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE", "RESOURCE_EXHAUSTED"},
+		},
+	}
+
+	writes := methodConfig{
+		Name: []methodName{
+			{Service: service, Method: "CreateMovie"},
+			{Service: service, Method: "DeleteMovie"},
+		},
+		Timeout: durationJSON(cfg.GrpcWriteTimeout),
+	}
+
+	if cfg.GrpcRetryMaxAttempts < 2 {
+		reads.RetryPolicy = nil
+	}
+
+	balancing := balancerConfig{
+		Policy:              cfg.GrpcLbPolicy,
+		ConsecutiveFailures: cfg.OutlierConsecutiveFailures,
+		BaseEjectionTime:    cfg.OutlierBaseEjectionTime.String(),
+		MaxEjectionPercent:  cfg.OutlierMaxEjectionPercent,
+	}
+
+	encoded, err := json.Marshal(serviceConfig{
+		LoadBalancingConfig: []map[string]balancerConfig{{balancerName: balancing}},
+		HealthCheckConfig:   healthCheckConfig{ServiceName: service},
+		MethodConfig:        []methodConfig{reads, writes},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// durationJSON writes the protobuf JSON form of a duration, which keeps
+// nanoseconds, so a sub-millisecond timeout doesn't round down to zero.
+func durationJSON(duration time.Duration) string {
+	return fmt.Sprintf("%d.%09ds", duration/time.Second, duration%time.Second)
+}
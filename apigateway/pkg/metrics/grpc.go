@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		conn *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, conn, opts...)
+
+		grpcClientDuration.WithLabelValues(method, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+
+		return err
+	}
+}
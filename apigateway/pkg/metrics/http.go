@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unmatchedRoute = "unmatched"
+
+func GinMiddleware() gin.HandlerFunc {
+	return func(context *gin.Context) {
+		start := time.Now()
+		context.Next()
+
+		route := context.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		httpRequestDuration.WithLabelValues(
+			context.Request.Method,
+			route,
+			strconv.Itoa(context.Writer.Status()),
+		).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,65 @@
+// Package contract checks what the gateway and the movies service must
+// agree on without running either of them.
+package contract
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	gatewayutil "apigateway/core/util"
+	moviesutil "movies/core/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// moviesReasons reads every Reason* constant from the movies service
+// source, so a reason added there without a gateway code fails here.
+func moviesReasons(t *testing.T) map[string]string {
+	file, err := parser.ParseFile(token.NewFileSet(), "../../movies/core/util/codes.go", nil, 0)
+	require.NoError(t, err)
+
+	reasons := make(map[string]string)
+	ast.Inspect(file, func(node ast.Node) bool {
+		spec, ok := node.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for index, name := range spec.Names {
+			if !strings.HasPrefix(name.Name, "Reason") || index >= len(spec.Values) {
+				continue
+			}
+			literal, ok := spec.Values[index].(*ast.BasicLit)
+			require.True(t, ok, "%s must be a string literal", name.Name)
+			value, err := strconv.Unquote(literal.Value)
+			require.NoError(t, err)
+			reasons[name.Name] = value
+		}
+		return true
+	})
+
+	require.NotEmpty(t, reasons)
+	return reasons
+}
+
+func TestEveryMoviesReasonIsAGatewayCode(t *testing.T) {
+	for name, reason := range moviesReasons(t) {
+		assert.True(t, gatewayutil.IsKnownCode(gatewayutil.ErrorCode(reason)), "%s = %q has no gateway code", name, reason)
+	}
+}
+
+func TestReasonsSurviveTheWire(t *testing.T) {
+	for name, reason := range moviesReasons(t) {
+		// The code is deliberately unrelated: the reason must win.
+		err := moviesutil.StatusError(codes.Unknown, reason, "upstream message")
+
+		apiErr := gatewayutil.ToAPIError(err)
+		assert.Equal(t, gatewayutil.ErrorCode(reason), apiErr.Code, name)
+		assert.NotContains(t, apiErr.Error(), "upstream message", "upstream messages aren't exposed")
+	}
+}
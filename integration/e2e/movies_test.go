@@ -0,0 +1,203 @@
+package e2e
+
+import (
+	"encoding/json"
+	"integration/harness"
+	"net/http"
+	"proto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type envelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   struct {
+		Code       string `json:"code"`
+		RequestID  string `json:"request_id"`
+		Violations []struct {
+			Field string `json:"field"`
+		} `json:"violations"`
+	} `json:"error"`
+}
+
+type movie struct {
+	Id    uint32 `json:"id"`
+	Title string `json:"title"`
+	Year  string `json:"year"`
+}
+
+type movieList struct {
+	Movies  []movie `json:"movies"`
+	More    bool    `json:"more"`
+	Page    uint32  `json:"page"`
+	Total   uint32  `json:"total"`
+	Results uint32  `json:"results"`
+}
+
+func decode(test *testing.T, res harness.Response) envelope {
+	test.Helper()
+
+	var body envelope
+	require.NoError(test, json.Unmarshal([]byte(res.Body), &body), res.Body)
+	return body
+}
+
+func seedMovies(h *harness.Harness) {
+	h.Seed(
+		&proto.Movie{Id: 1, Title: "The Matrix", Year: "1999"},
+		&proto.Movie{Id: 2, Title: "Inception", Year: "2010"},
+		&proto.Movie{Id: 3, Title: "Interstellar", Year: "2014"},
+	)
+}
+
+func TestCreateMovie(test *testing.T) {
+	h := harness.New(test)
+
+	payload, err := json.Marshal(movie{Title: "Alice in Wonderland", Year: "2010"})
+	require.NoError(test, err)
+
+	res := h.Post("/v1/movies", payload)
+	require.Equal(test, http.StatusCreated, res.StatusCode, res.Body)
+
+	body := decode(test, res)
+	assert.True(test, body.Success)
+
+	var created movie
+	require.NoError(test, json.Unmarshal(body.Data, &created))
+	assert.NotZero(test, created.Id)
+	assert.Equal(test, "Alice in Wonderland", created.Title)
+
+	stored, err := h.Repository.FindById(test.Context(), &proto.MovieIdRequest{Id: created.Id})
+	require.NoError(test, err)
+	assert.Equal(test, "2010", stored.Year)
+}
+
+func TestCreateMovieBadRequest(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Post("/v1/movies", []byte(`{"title":"Alice in Wonderland"}`))
+	assert.Equal(test, http.StatusBadRequest, res.StatusCode)
+
+	body := decode(test, res)
+	assert.False(test, body.Success)
+	assert.Equal(test, "VALIDATION_FAILED", body.Error.Code)
+	require.Len(test, body.Error.Violations, 1)
+	assert.Equal(test, "year", body.Error.Violations[0].Field)
+}
+
+// TestCreateMovieRejectedByMoviesService covers input the gateway lets
+// through but the movies service validation interceptor refuses.
+func TestCreateMovieRejectedByMoviesService(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Post("/v1/movies", []byte(`{"title":"Alice in Wonderland","year":"20x0"}`))
+	assert.Equal(test, http.StatusBadRequest, res.StatusCode)
+
+	body := decode(test, res)
+	assert.Equal(test, "VALIDATION_FAILED", body.Error.Code)
+	require.Len(test, body.Error.Violations, 1)
+	assert.Equal(test, "year", body.Error.Violations[0].Field)
+}
+
+func TestGetMovie(test *testing.T) {
+	h := harness.New(test)
+	seedMovies(h)
+
+	res := h.Get("/v1/movies/2")
+	require.Equal(test, http.StatusOK, res.StatusCode, res.Body)
+
+	var found movie
+	require.NoError(test, json.Unmarshal(decode(test, res).Data, &found))
+	assert.Equal(test, movie{Id: 2, Title: "Inception", Year: "2010"}, found)
+}
+
+func TestGetMovieNotFound(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Get("/v1/movies/9999999")
+	assert.Equal(test, http.StatusNotFound, res.StatusCode)
+
+	body := decode(test, res)
+	assert.False(test, body.Success)
+	assert.Equal(test, "MOVIE_NOT_FOUND", body.Error.Code)
+	assert.NotEmpty(test, body.Error.RequestID)
+}
+
+func TestGetMovieList(test *testing.T) {
+	h := harness.New(test)
+	seedMovies(h)
+
+	res := h.Get("/v1/movies?pageNumber=1&resultsPerPage=2")
+	require.Equal(test, http.StatusOK, res.StatusCode, res.Body)
+
+	var list movieList
+	require.NoError(test, json.Unmarshal(decode(test, res).Data, &list))
+	assert.Equal(test, uint32(3), list.Total)
+	assert.Equal(test, uint32(1), list.Page)
+	assert.True(test, list.More)
+	require.Len(test, list.Movies, 2)
+	assert.Equal(test, "Interstellar", list.Movies[0].Title)
+	assert.Equal(test, "Inception", list.Movies[1].Title)
+}
+
+func TestGetMovieListPageNotFound(test *testing.T) {
+	h := harness.New(test)
+	seedMovies(h)
+
+	res := h.Get("/v1/movies?pageNumber=10&resultsPerPage=2")
+	assert.Equal(test, http.StatusNotFound, res.StatusCode)
+	assert.Equal(test, "PAGE_NOT_FOUND", decode(test, res).Error.Code)
+}
+
+func TestDeleteMovie(test *testing.T) {
+	h := harness.New(test)
+	seedMovies(h)
+
+	res := h.Delete("/v1/movies/1")
+	assert.Equal(test, http.StatusNoContent, res.StatusCode)
+
+	res = h.Get("/v1/movies/1")
+	assert.Equal(test, http.StatusNotFound, res.StatusCode)
+}
+
+func TestDeleteMovieNotFound(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Delete("/v1/movies/9999999")
+	assert.Equal(test, http.StatusNotFound, res.StatusCode)
+	assert.Equal(test, "MOVIE_NOT_FOUND", decode(test, res).Error.Code)
+}
+
+func TestRequestIDIsEchoed(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Do(http.MethodGet, "/v1/movies/9999999", nil, http.Header{"X-Request-Id": {"e2e-request-id"}})
+	assert.Equal(test, "e2e-request-id", res.Header.Get("X-Request-ID"))
+	assert.Equal(test, "e2e-request-id", decode(test, res).Error.RequestID)
+}
+
+func TestReadyz(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Get("/readyz")
+	assert.Equal(test, http.StatusOK, res.StatusCode, res.Body)
+
+	h.Readiness.SetReady(false)
+	res = h.Get("/readyz")
+	assert.Equal(test, http.StatusServiceUnavailable, res.StatusCode)
+}
+
+func TestLegacyHealthAlias(test *testing.T) {
+	h := harness.New(test)
+
+	res := h.Get("/v1/health")
+	assert.Equal(test, http.StatusOK, res.StatusCode, res.Body)
+	assert.Equal(test, "true", res.Header.Get("Deprecation"))
+
+	h.Readiness.SetReady(false)
+	res = h.Get("/v1/health")
+	assert.Equal(test, http.StatusServiceUnavailable, res.StatusCode)
+}
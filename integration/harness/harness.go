@@ -0,0 +1,143 @@
+// Package harness boots the gateway router and the movies gRPC service in
+// a single process. The two talk over an in-memory bufconn listener and the
+// movies service is backed by the mock repository, so the whole
+// HTTP → gRPC → repository path runs under go test without containers.
+package harness
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	gatewayapp "apigateway/core/app"
+	gatewayconfig "apigateway/core/config"
+	"apigateway/core/handler"
+	"apigateway/infra/clients"
+	moviesapp "movies/core/app"
+	"movies/core/usecases"
+	"movies/infra/persistence/mock"
+	"proto"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const bufferSize = 1024 * 1024
+
+// bufconnAddress only needs to survive the gateway resolver; the dialer
+// ignores it and always hands back a bufconn connection.
+const bufconnAddress = "127.0.0.1:9090"
+
+type Harness struct {
+	BaseURL    string
+	Repository *mock.MoviesRepositoryMock
+	Readiness  *handler.Readiness
+
+	server *httptest.Server
+	t      testing.TB
+}
+
+type Response struct {
+	Body       string
+	StatusCode int
+	Header     http.Header
+}
+
+// New starts both services and registers their shutdown with t.Cleanup.
+func New(t testing.TB) *Harness {
+	t.Helper()
+
+	log := zap.NewNop()
+	repository := mock.NewMoviesRepositoryMock().(*mock.MoviesRepositoryMock)
+
+	health := &usecases.HealthUsecase{Ping: func(context.Context) error { return nil }}
+	grpcServer := moviesapp.NewGRPCServer(log, log, repository, health)
+
+	listener := bufconn.Listen(bufferSize)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	cfg, err := gatewayconfig.Load(nil)
+	if err != nil {
+		t.Fatalf("failed to load gateway config: %v", err)
+	}
+	cfg.Env = "test"
+	cfg.GrpcServerAddress = bufconnAddress
+	cfg.GrpcTLSCertFile = ""
+	cfg.HTTPTLSCertFile = ""
+
+	grpcClient, err := clients.NewGrpcClient(&cfg, log, grpc.WithContextDialer(
+		func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		},
+	))
+	if err != nil {
+		t.Fatalf("failed to create gRPC client: %v", err)
+	}
+	t.Cleanup(func() { grpcClient.Close() })
+
+	readiness := handler.NewReadiness()
+
+	server := httptest.NewServer(gatewayapp.SetupRouter(&cfg, log, grpcClient, readiness))
+	t.Cleanup(server.Close)
+
+	return &Harness{
+		BaseURL:    server.URL,
+		Repository: repository,
+		Readiness:  readiness,
+		server:     server,
+		t:          t,
+	}
+}
+
+// Seed stores movies directly in the repository, bypassing the API.
+func (h *Harness) Seed(movies ...*proto.Movie) {
+	h.Repository.Seed(movies)
+}
+
+func (h *Harness) Get(path string) Response {
+	return h.Do(http.MethodGet, path, nil, nil)
+}
+
+func (h *Harness) Post(path string, payload []byte) Response {
+	return h.Do(http.MethodPost, path, payload, http.Header{"Content-Type": {"application/json"}})
+}
+
+func (h *Harness) Delete(path string) Response {
+	return h.Do(http.MethodDelete, path, nil, nil)
+}
+
+func (h *Harness) Do(method, path string, payload []byte, header http.Header) Response {
+	h.t.Helper()
+
+	req, err := http.NewRequest(method, h.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		h.t.Fatalf("failed to create %s request: %v", method, err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	res, err := h.server.Client().Do(req)
+	if err != nil {
+		h.t.Fatalf("failed to execute request: %v", err)
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		h.t.Fatalf("failed to read response body: %v", err)
+	}
+
+	return Response{
+		Body:       string(body),
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+	}
+}
@@ -0,0 +1,144 @@
+// Command devcerts generates a throwaway CA plus server and client
+// certificates for running the gateway and movies service with mTLS under
+// docker compose. Never use its output outside local development.
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"flag"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const validity = 365 * 24 * time.Hour
+
+func main() {
+	out := flag.String("out", "certs", "directory to write the PEM files to")
+	hosts := flag.String("hosts", "movies,localhost,127.0.0.1", "comma separated SANs for the movies server certificate")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal(err)
+	}
+
+	caKey, caCert, err := newCA()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := writeCertificate(*out, "ca", caCert, nil); err != nil {
+		log.Fatal(err)
+	}
+
+	serverTemplate := leafTemplate("movies", x509.ExtKeyUsageServerAuth)
+	for _, host := range strings.Split(*hosts, ",") {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+
+	if err := issue(*out, "movies", serverTemplate, caCert, caKey); err != nil {
+		log.Fatal(err)
+	}
+
+	clientTemplate := leafTemplate("apigateway", x509.ExtKeyUsageClientAuth)
+	if err := issue(*out, "apigateway", clientTemplate, caCert, caKey); err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("certificates written to %s", *out)
+}
+
+func newCA() (*ecdsa.PrivateKey, *x509.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serialNumber(),
+		Subject:               pkix.Name{CommonName: "movies dev CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(validity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	return key, cert, err
+}
+
+func leafTemplate(commonName string, usage x509.ExtKeyUsage) *x509.Certificate {
+	return &x509.Certificate{
+		SerialNumber: serialNumber(),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+}
+
+func issue(out, name string, template, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	return writeCertificate(out, name, cert, key)
+}
+
+func writeCertificate(out, name string, cert *x509.Certificate, key *ecdsa.PrivateKey) error {
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if err := os.WriteFile(filepath.Join(out, name+".pem"), certPEM, 0o644); err != nil {
+		return err
+	}
+
+	if key == nil {
+		return nil
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return err
+	}
+
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+	return os.WriteFile(filepath.Join(out, name+"-key.pem"), keyPEM, 0o600)
+}
+
+func serialNumber() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return serial
+}
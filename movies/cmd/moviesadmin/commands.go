@@ -0,0 +1,208 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"movies/infra/persistence/mongodb"
+	"movies/seed"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func runMigrate(ctx context.Context, admin *admin, args []string) error {
+	flags := newFlagSet("migrate")
+	status := flags.Bool("status", false, "list migrations and whether they were applied, without running them")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if !*status {
+		logger, err := zap.NewDevelopment()
+		if err != nil {
+			return err
+		}
+
+		if err := mongodb.Migrate(ctx, admin.db, admin.cfg.DbCollection, logger); err != nil {
+			return err
+		}
+	}
+
+	statuses, err := mongodb.MigrationStatuses(ctx, admin.db)
+	if err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "VERSION\tNAME\tAPPLIED AT")
+	for _, migration := range statuses {
+		appliedAt := "pending"
+		if !migration.AppliedAt.IsZero() {
+			appliedAt = migration.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(writer, "%d\t%s\t%s\n", migration.Version, migration.Name, appliedAt)
+	}
+	return writer.Flush()
+}
+
+func runReindex(ctx context.Context, admin *admin, args []string) error {
+	if err := parseFlags(newFlagSet("reindex"), args); err != nil {
+		return err
+	}
+
+	dropped, err := mongodb.RebuildIndexes(ctx, admin.collection)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("ensured the expected indexes and dropped %d others %v", len(dropped), dropped)
+	return nil
+}
+
+func runReseed(ctx context.Context, admin *admin, args []string) error {
+	flags := newFlagSet("reseed")
+	path := flags.String("path", admin.cfg.SeedPath, "seed file, the embedded seed when empty (SEED_PATH)")
+	format := flags.String("format", admin.cfg.SeedFormat, "json, ndjson or csv, detected from the extension when empty (SEED_FORMAT)")
+	modeName := flags.String("mode", string(seed.ModeUpsert), "upsert or if-empty")
+	dryRun := flags.Bool("dry-run", false, "only report what would change")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	mode, err := seed.ParseMode(*modeName)
+	if err != nil {
+		return err
+	}
+
+	reader, err := seed.Open(*path, seed.Format(*format))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	report, err := seed.Apply(ctx, mongodb.NewSeedStore(admin.collection), reader, seed.Options{Mode: mode, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+
+	if report.Skipped {
+		log.Printf("collection is not empty, nothing to do in %s mode", mode)
+		return nil
+	}
+
+	prefix := ""
+	if report.DryRun {
+		prefix = "dry run: "
+	}
+	log.Printf("%sread %d, inserted %d, updated %d, unchanged %d",
+		prefix, report.Read, report.Inserted, report.Updated, report.Unchanged)
+	return nil
+}
+
+// runRecomputeID is the boot-time counter initialisation on its own. The
+// counter lives in each replica's memory, so this reports the value they
+// will start from and checks the unique index that guards against clashes.
+func runRecomputeID(ctx context.Context, admin *admin, args []string) error {
+	if err := parseFlags(newFlagSet("recompute-id"), args); err != nil {
+		return err
+	}
+
+	maxID, err := mongodb.MaxID(ctx, admin.collection)
+	if err != nil {
+		return err
+	}
+
+	if err := mongodb.CreateIndexes(ctx, admin.collection); err != nil {
+		return fmt.Errorf("ensuring the unique id index: %w", err)
+	}
+
+	log.Printf("highest id is %d, replicas allocate from %d after a restart", maxID, maxID+1)
+	return nil
+}
+
+func runDump(ctx context.Context, admin *admin, args []string) error {
+	flags := newFlagSet("dump")
+	out := flags.String("out", fmt.Sprintf("%s-%s.bson.gz", admin.cfg.DbCollection, time.Now().UTC().Format("20060102T150405Z")), "archive file to write")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	archive := gzip.NewWriter(file)
+	count, err := mongodb.Dump(ctx, admin.collection, archive)
+	if err == nil {
+		err = archive.Close()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		return err
+	}
+
+	log.Printf("dumped %d documents to %s", count, *out)
+	return nil
+}
+
+func runRestore(ctx context.Context, admin *admin, args []string) error {
+	flags := newFlagSet("restore")
+	drop := flags.Bool("drop", false, "replace the collection once the whole archive is restored; without it the collection must be empty")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errUsage
+	}
+
+	file, err := os.Open(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive, err := gzip.NewReader(file)
+	if err != nil {
+		return err
+	}
+
+	if *drop {
+		count, err := mongodb.RestoreReplacing(ctx, admin.collection, archive)
+		if err != nil {
+			return fmt.Errorf("collection left unchanged, restore failed after %d documents: %w", count, err)
+		}
+
+		log.Printf("replaced the collection with %d documents from %s", count, flags.Arg(0))
+		return nil
+	}
+
+	empty, err := mongodb.NewSeedStore(admin.collection).Empty(ctx)
+	if err != nil {
+		return err
+	}
+	if !empty {
+		return errors.New("collection is not empty, pass -drop to replace it")
+	}
+
+	count, err := mongodb.Restore(ctx, admin.collection, archive)
+	if err != nil {
+		return fmt.Errorf("restored %d documents before failing: %w", count, err)
+	}
+
+	if err := mongodb.CreateIndexes(ctx, admin.collection); err != nil {
+		return err
+	}
+
+	log.Printf("restored %d documents from %s", count, flags.Arg(0))
+	return nil
+}
@@ -0,0 +1,148 @@
+// Command moviesadmin runs one-off maintenance against the movies MongoDB
+// collection: migrations, index rebuilds, reseeding, the ID counter,
+// document validation and archive dumps. It reads the same environment as
+// the service (MONGO_DB_URI, MONGO_DB, MONGO_DB_COLLECTION, ...).
+//
+//	moviesadmin [-timeout d] <command> [command flags] [arguments]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"movies/core/config"
+	"movies/infra/persistence/mongodb"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	run     func(ctx context.Context, admin *admin, args []string) error
+}
+
+// commands is filled in init because the flag set usage walks it, which
+// would otherwise be an initialization cycle.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"migrate", "migrate [-status]", "apply pending migrations, or list them", runMigrate},
+		{"reindex", "reindex", "create the expected indexes and drop the others", runReindex},
+		{"reseed", "reseed [-path file] [-format f] [-mode upsert|if-empty] [-dry-run]", "apply a seed file to the collection", runReseed},
+		{"recompute-id", "recompute-id", "recompute the ID counter from the highest stored id", runRecomputeID},
+		{"validate", "validate [-fix]", "check every movie against the domain rules", runValidate},
+		{"dump", "dump [-out file]", "write the collection to a gzipped BSON archive", runDump},
+		{"restore", "restore [-drop] <file>", "load a dump archive into the collection", runRestore},
+	}
+}
+
+// admin is what the commands share: the configured database and
+// collection, connected before the command runs.
+type admin struct {
+	cfg        config.Config
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("moviesadmin: ")
+
+	timeout := flag.Duration("timeout", 30*time.Minute, "overall deadline for the command")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, command := range commands {
+		if command.name == name {
+			err := run(command, args, *timeout)
+			switch {
+			case err == nil, errors.Is(err, flag.ErrHelp):
+			case errors.Is(err, errUsage):
+				os.Exit(2)
+			default:
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	log.Printf("unknown command %q", name)
+	usage()
+	os.Exit(2)
+}
+
+func run(command command, args []string, timeout time.Duration) error {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	client, err := mongodb.Connect(&cfg)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+
+	db := client.Database(cfg.DbName)
+	admin := &admin{cfg: cfg, client: client, db: db, collection: db.Collection(cfg.DbCollection)}
+
+	return command.run(ctx, admin, args)
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: moviesadmin [flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, command := range commands {
+		fmt.Fprintf(out, "  %-70s %s\n", command.usage, command.summary)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// errUsage reports bad command flags or arguments once the usage has been
+// printed; main turns it into exit status 2. Commands return it rather than
+// exiting so run still disconnects from Mongo.
+var errUsage = errors.New("usage")
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.Usage = func() {
+		for _, command := range commands {
+			if command.name == name {
+				fmt.Fprintf(flags.Output(), "Usage: moviesadmin %s\n", command.usage)
+			}
+		}
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+// parseFlags parses args into flags, mapping a parse failure (already
+// reported by the flag package) to errUsage and passing -help through.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	return nil
+}
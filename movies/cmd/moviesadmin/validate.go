@@ -0,0 +1,115 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"movies/core/util"
+	"movies/core/validation"
+	"movies/infra/persistence/mongodb"
+	"os"
+	"proto"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+)
+
+var (
+	yearInTitle = regexp.MustCompile(`\(([0-9]{4})\)\s*$`)
+	anyYear     = regexp.MustCompile(`[0-9]{4}`)
+)
+
+// runValidate checks every stored movie against the rules the API
+// enforces on create. With -fix, movies the fix heuristics can repair are
+// rewritten; the rest are only reported.
+func runValidate(ctx context.Context, admin *admin, args []string) error {
+	flags := newFlagSet("validate")
+	apply := flags.Bool("fix", false, "rewrite the movies that can be repaired automatically")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "ID\tFIELD\tPROBLEM\tFIX")
+
+	var checked, invalid, fixed int
+	err := mongodb.ForEachMovie(ctx, admin.collection, func(movie *proto.Movie) error {
+		checked++
+
+		violations := violationsOf(movie)
+		if len(violations) == 0 {
+			return nil
+		}
+		invalid++
+
+		repaired := fix(movie)
+		action := "manual"
+		if repaired != nil {
+			action = fmt.Sprintf("title=%q year=%q", repaired.Title, repaired.Year)
+			if *apply {
+				if err := mongodb.UpdateMovie(ctx, admin.collection, repaired); err != nil {
+					return err
+				}
+				fixed++
+				action = "fixed: " + action
+			}
+		}
+
+		for _, violation := range violations {
+			fmt.Fprintf(writer, "%d\t%s\t%s\t%s\n", movie.Id, violation.Field, violation.Description, action)
+		}
+		return nil
+	})
+	writer.Flush()
+	if err != nil {
+		return err
+	}
+
+	log.Printf("checked %d movies, %d invalid, %d fixed", checked, invalid, fixed)
+	if invalid > fixed {
+		return fmt.Errorf("%d movies still need fixing", invalid-fixed)
+	}
+	return nil
+}
+
+func violationsOf(movie *proto.Movie) []util.FieldViolation {
+	violations := validation.Validate(movie)
+	if movie.Id == 0 {
+		violations = append(violations, util.FieldViolation{Field: "id", Description: "must be greater than 0"})
+	}
+	return violations
+}
+
+// fix returns a repaired copy of movie, or nil when it can't be repaired
+// without a human: whitespace is trimmed, over-long titles truncated and a
+// missing or malformed year taken from the year in the value or from a
+// trailing "(1999)" in the title.
+func fix(movie *proto.Movie) *proto.Movie {
+	if movie.Id == 0 {
+		return nil
+	}
+
+	repaired := &proto.Movie{
+		Id:    movie.Id,
+		Title: strings.TrimSpace(movie.Title),
+		Year:  strings.TrimSpace(movie.Year),
+	}
+
+	if !validation.YearPattern.MatchString(repaired.Year) {
+		if match := anyYear.FindString(repaired.Year); match != "" {
+			repaired.Year = match
+		} else if match := yearInTitle.FindStringSubmatch(repaired.Title); match != nil {
+			repaired.Year = match[1]
+		}
+	}
+
+	if utf8.RuneCountInString(repaired.Title) > validation.MaxTitleLength {
+		repaired.Title = strings.TrimSpace(string([]rune(repaired.Title)[:validation.MaxTitleLength]))
+	}
+
+	if len(violationsOf(repaired)) > 0 {
+		return nil
+	}
+	return repaired
+}
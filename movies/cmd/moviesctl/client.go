@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"pkg/tlsreload"
+	"proto"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type connection struct {
+	addr       string
+	certFile   string
+	keyFile    string
+	caFile     string
+	serverName string
+}
+
+// cli is shared by the commands. The connection is opened on first use so
+// commands like completion work without a reachable service.
+type cli struct {
+	conn    connection
+	timeout time.Duration
+	out     *printer
+
+	grpcConn *grpc.ClientConn
+	movies   proto.MovieServiceClient
+}
+
+func (c *cli) client() (proto.MovieServiceClient, error) {
+	if c.movies != nil {
+		return c.movies, nil
+	}
+
+	transportCredentials := insecure.NewCredentials()
+	if c.conn.certFile != "" {
+		if c.conn.keyFile == "" || c.conn.caFile == "" {
+			return nil, errors.New("mTLS needs -tls-cert-file, -tls-key-file and -tls-ca-file")
+		}
+
+		reloader, err := tlsreload.New(c.conn.certFile, c.conn.keyFile, c.conn.caFile)
+		if err != nil {
+			return nil, err
+		}
+		transportCredentials = credentials.NewTLS(reloader.ClientConfig(c.conn.serverName))
+	}
+
+	conn, err := grpc.NewClient(c.conn.addr, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, err
+	}
+
+	c.grpcConn = conn
+	c.movies = proto.NewMovieServiceClient(conn)
+	return c.movies, nil
+}
+
+// call bounds a single RPC by the -timeout flag.
+func (c *cli) call(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+func (c *cli) close() {
+	if c.grpcConn != nil {
+		c.grpcConn.Close()
+		c.grpcConn = nil
+	}
+}
+
+func fieldViolations(st *status.Status) []*errdetails.BadRequest_FieldViolation {
+	var violations []*errdetails.BadRequest_FieldViolation
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			violations = append(violations, badRequest.GetFieldViolations()...)
+		}
+	}
+	return violations
+}
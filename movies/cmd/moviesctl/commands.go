@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"movies/seed"
+	"os"
+	"proto"
+	"strconv"
+)
+
+// exportPageSize is the largest page the service accepts.
+const exportPageSize = 100
+
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		for _, command := range commands {
+			if command.name == name {
+				fmt.Fprintf(flags.Output(), "Usage: moviesctl %s\n", command.usage)
+			}
+		}
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+func parseID(value string) (uint32, error) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("invalid movie id %q", value)
+	}
+	return uint32(id), nil
+}
+
+func runGet(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("get")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cli.call(ctx)
+	defer cancel()
+
+	movie, err := client.GetMovie(ctx, &proto.MovieIdRequest{Id: id})
+	if err != nil {
+		return err
+	}
+	return cli.out.movie(movie)
+}
+
+func runList(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("list")
+	page := flags.Uint("page", 1, "page to show")
+	limit := flags.Uint("limit", 20, "movies per page, at most 100")
+	all := flags.Bool("all", false, "follow every page from -page on")
+	flags.Parse(args)
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	list := movieListView{Movies: []movieView{}}
+	for current := uint32(*page); ; current++ {
+		callCtx, cancel := cli.call(ctx)
+		response, err := client.GetMovies(callCtx, &proto.GetMoviesRequest{Page: current, Limit: uint32(*limit)})
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for _, movie := range response.GetMovies() {
+			list.Movies = append(list.Movies, newMovieView(movie))
+		}
+		list.Page, list.Total = response.GetPage(), response.GetTotal()
+		list.More = hasMore(response, uint32(*limit))
+
+		if !*all || !list.More {
+			break
+		}
+	}
+
+	if *all {
+		list.Page = 0
+	}
+	return cli.out.movies(list)
+}
+
+func runCreate(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("create")
+	title := flags.String("title", "", "movie title")
+	year := flags.String("year", "", "release year, four digits")
+	flags.Parse(args)
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := cli.call(ctx)
+	defer cancel()
+
+	movie, err := client.CreateMovie(ctx, &proto.Movie{Title: *title, Year: *year})
+	if err != nil {
+		return err
+	}
+	return cli.out.movie(movie)
+}
+
+func runDelete(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("delete")
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	ids := make([]uint32, 0, flags.NArg())
+	for _, arg := range flags.Args() {
+		id, err := parseID(arg)
+		if err != nil {
+			return err
+		}
+		ids = append(ids, id)
+	}
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	deleted := []uint32{}
+	for _, id := range ids {
+		callCtx, cancel := cli.call(ctx)
+		_, err := client.DeleteMovie(callCtx, &proto.MovieIdRequest{Id: id})
+		cancel()
+		if err != nil {
+			cli.out.deleted(deleted)
+			return fmt.Errorf("movie %d: %w", id, err)
+		}
+		deleted = append(deleted, id)
+	}
+
+	return cli.out.deleted(deleted)
+}
+
+// runImport creates every movie of a seed-style file through the API.
+// IDs in the file are ignored; the service assigns new ones.
+func runImport(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("import")
+	format := flags.String("format", "", "file format, detected from the extension by default (json for -)")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	reader, err := openInput(flags.Arg(0), seed.Format(*format))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	reader.IDOptional = true
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	var created []movieView
+	for {
+		movie, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		callCtx, cancel := cli.call(ctx)
+		response, err := client.CreateMovie(callCtx, &proto.Movie{Title: movie.Title, Year: movie.Year})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("importing %q after %d movies: %w", movie.Title, len(created), err)
+		}
+		created = append(created, newMovieView(response))
+	}
+
+	return cli.out.movies(movieListView{Movies: created, Total: uint32(len(created))})
+}
+
+// runExport pages through the whole catalogue and writes it in a format
+// the seed loader and import accept.
+func runExport(ctx context.Context, cli *cli, args []string) error {
+	flags := newFlagSet("export")
+	format := flags.String("format", "", "file format, detected from the extension by default (json for -)")
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	path := flags.Arg(0)
+	if *format == "" {
+		*format = string(seed.FormatJSON)
+		if path != "" && path != "-" {
+			*format = string(seed.DetectFormat(path))
+		}
+	}
+
+	client, err := cli.client()
+	if err != nil {
+		return err
+	}
+
+	out := io.Writer(os.Stdout)
+	if path != "" && path != "-" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	writer, err := seed.NewWriter(out, seed.Format(*format))
+	if err != nil {
+		return err
+	}
+
+	for page := uint32(1); ; page++ {
+		callCtx, cancel := cli.call(ctx)
+		response, err := client.GetMovies(callCtx, &proto.GetMoviesRequest{Page: page, Limit: exportPageSize})
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for _, movie := range response.GetMovies() {
+			if err := writer.Write(seed.Movie{Id: movie.GetId(), Title: movie.GetTitle(), Year: movie.GetYear()}); err != nil {
+				return err
+			}
+		}
+
+		if !hasMore(response, exportPageSize) {
+			break
+		}
+	}
+
+	return writer.Close()
+}
+
+// hasMore works it out from the total, like the gateway does, since the
+// service leaves MovieListResponse.More unset.
+func hasMore(response *proto.MovieListResponse, limit uint32) bool {
+	return uint64(response.GetPage())*uint64(limit) < uint64(response.GetTotal())
+}
+
+func openInput(path string, format seed.Format) (*seed.Reader, error) {
+	if path != "-" {
+		return seed.Open(path, format)
+	}
+
+	if format == "" {
+		format = seed.FormatJSON
+	}
+	return seed.NewReader(os.Stdin, format)
+}
+
+func runCompletion(ctx context.Context, cli *cli, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: moviesctl completion bash|zsh")
+	}
+
+	var script string
+	switch args[0] {
+	case "bash":
+		script = bashCompletion()
+	case "zsh":
+		script = zshCompletion()
+	default:
+		return fmt.Errorf("no completion for shell %q, expected bash or zsh", args[0])
+	}
+
+	_, err := fmt.Fprint(os.Stdout, script)
+	return err
+}
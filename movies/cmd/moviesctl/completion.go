@@ -0,0 +1,114 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func globalFlags() []string {
+	var names []string
+	flag.VisitAll(func(f *flag.Flag) {
+		names = append(names, "-"+f.Name)
+	})
+	return names
+}
+
+func commandNames() []string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.name
+	}
+	return names
+}
+
+// bashCompletion completes global flags and commands, then the flags and
+// arguments of the command on the line. Load it with
+//
+//	source <(moviesctl completion bash)
+func bashCompletion() string {
+	var cases strings.Builder
+	for _, command := range commands {
+		words := strings.Join(command.flags, " ")
+		action := fmt.Sprintf(`COMPREPLY=($(compgen -W "%s" -- "$cur"))`, words)
+		if len(command.args) == 1 && command.args[0] == "file" {
+			action = fmt.Sprintf(`COMPREPLY=($(compgen -W "%s" -- "$cur") $(compgen -f -- "$cur"))`, words)
+		} else if len(command.args) > 0 {
+			action = fmt.Sprintf(`COMPREPLY=($(compgen -W "%s" -- "$cur"))`, strings.Join(append(command.flags, command.args...), " "))
+		}
+		fmt.Fprintf(&cases, "        %s) %s ;;\n", command.name, action)
+	}
+
+	return fmt.Sprintf(`# bash completion for moviesctl
+_moviesctl() {
+    local cur="${COMP_WORDS[COMP_CWORD]}" command="" i=1
+    # Every global flag takes a value, so skip the word after it.
+    while (( i < COMP_CWORD )); do
+        case "${COMP_WORDS[i]}" in
+            -*=*) ;;
+            -*) (( i++ )) ;;
+            *) command="${COMP_WORDS[i]}"; break ;;
+        esac
+        (( i++ ))
+    done
+
+    if [[ -z "$command" ]]; then
+        COMPREPLY=($(compgen -W "%s %s" -- "$cur"))
+        return
+    fi
+
+    case "$command" in
+%s    esac
+}
+complete -o default -F _moviesctl moviesctl
+`, strings.Join(globalFlags(), " "), strings.Join(commandNames(), " "), cases.String())
+}
+
+// zshCompletion offers the same words through zsh's _arguments. Load it
+// with
+//
+//	source <(moviesctl completion zsh)
+func zshCompletion() string {
+	var describe, cases strings.Builder
+	for _, command := range commands {
+		fmt.Fprintf(&describe, "        '%s:%s'\n", command.name, command.summary)
+
+		words := append([]string{}, command.flags...)
+		action := "compadd -- " + strings.Join(words, " ")
+		if len(command.args) == 1 && command.args[0] == "file" {
+			action = fmt.Sprintf("compadd -- %s; _files", strings.Join(words, " "))
+		} else if len(command.args) > 0 {
+			action = "compadd -- " + strings.Join(append(words, command.args...), " ")
+		}
+		fmt.Fprintf(&cases, "        %s) %s ;;\n", command.name, action)
+	}
+
+	return fmt.Sprintf(`#compdef moviesctl
+_moviesctl() {
+    local -a commands
+    commands=(
+%s    )
+
+    local command i=2
+    # Every global flag takes a value, so skip the word after it.
+    while (( i < CURRENT )); do
+        case $words[i] in
+            -*=*) ;;
+            -*) (( i++ )) ;;
+            *) command=$words[i]; break ;;
+        esac
+        (( i++ ))
+    done
+
+    if [[ -z $command ]]; then
+        compadd -- %s
+        _describe 'command' commands
+        return
+    fi
+
+    case $command in
+%s    esac
+}
+compdef _moviesctl moviesctl
+`, describe.String(), strings.Join(globalFlags(), " "), cases.String())
+}
@@ -0,0 +1,139 @@
+// Command moviesctl talks to the movies gRPC API directly, for ops and QA
+// work that would otherwise go through the gateway or grpcurl.
+//
+//	moviesctl [flags] <command> [command flags] [arguments]
+//
+// Connection flags default to the same settings the service reads from
+// the environment (LISTEN_PORT, GRPC_TLS_*), so a shell with the service
+// .env loaded needs no flags at all.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/joho/godotenv"
+	"google.golang.org/grpc/status"
+)
+
+type command struct {
+	name    string
+	usage   string
+	summary string
+	// flags and args feed the completion scripts; args is "file" or a
+	// fixed word list.
+	flags []string
+	args  []string
+	run   func(ctx context.Context, cli *cli, args []string) error
+}
+
+// commands is filled in init because completion walks it, which would
+// otherwise be an initialization cycle.
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "get", usage: "get <id>", summary: "show one movie", run: runGet},
+		{name: "list", usage: "list [-page n] [-limit n] [-all]", summary: "list movies, newest first",
+			flags: []string{"-page", "-limit", "-all"}, run: runList},
+		{name: "create", usage: "create -title <title> -year <year>", summary: "create a movie",
+			flags: []string{"-title", "-year"}, run: runCreate},
+		{name: "delete", usage: "delete <id>...", summary: "delete movies", run: runDelete},
+		{name: "import", usage: "import [-format json|ndjson|csv] <file|->", summary: "create every movie in a seed-style file",
+			flags: []string{"-format"}, args: []string{"file"}, run: runImport},
+		{name: "export", usage: "export [-format json|ndjson|csv] [file|-]", summary: "write every movie to a seed-style file",
+			flags: []string{"-format"}, args: []string{"file"}, run: runExport},
+		{name: "completion", usage: "completion bash|zsh", summary: "print a shell completion script",
+			args: []string{"bash", "zsh"}, run: runCompletion},
+	}
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("moviesctl: ")
+
+	// Only the few variables the flags default to are read: loading the
+	// whole server config would make every command, --help included,
+	// fail on server-only settings or an unreachable Vault.
+	_ = godotenv.Load()
+
+	conn := connection{}
+	flag.StringVar(&conn.addr, "addr", "localhost:"+envOr("LISTEN_PORT", "9090"), "movies gRPC address")
+	flag.StringVar(&conn.certFile, "tls-cert-file", envOr("GRPC_TLS_CERT_FILE", ""), "client certificate for mTLS (GRPC_TLS_CERT_FILE)")
+	flag.StringVar(&conn.keyFile, "tls-key-file", envOr("GRPC_TLS_KEY_FILE", ""), "client key for mTLS (GRPC_TLS_KEY_FILE)")
+	flag.StringVar(&conn.caFile, "tls-ca-file", envOr("GRPC_TLS_CA_FILE", ""), "CA bundle to verify the server (GRPC_TLS_CA_FILE)")
+	flag.StringVar(&conn.serverName, "tls-server-name", "movies", "name expected in the server certificate")
+	timeout := flag.Duration("timeout", 10*time.Second, "deadline for each call")
+	output := flag.String("o", "table", "output format: table, json or yaml")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	out, err := newPrinter(*output, os.Stdout)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	cli := &cli{conn: conn, timeout: *timeout, out: out}
+	defer cli.close()
+
+	name, args := flag.Arg(0), flag.Args()[1:]
+	for _, command := range commands {
+		if command.name != name {
+			continue
+		}
+
+		if err := command.run(context.Background(), cli, args); err != nil {
+			cli.close()
+			log.Fatal(describe(err))
+		}
+		return
+	}
+
+	log.Printf("unknown command %q", name)
+	usage()
+	os.Exit(2)
+}
+
+// envOr treats empty variables as unset, like the service config does.
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintln(out, "Usage: moviesctl [flags] <command> [command flags] [arguments]")
+	fmt.Fprintln(out, "\nCommands:")
+	for _, command := range commands {
+		fmt.Fprintf(out, "  %-45s %s\n", command.usage, command.summary)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// describe spells out gRPC errors, including the field violations the
+// validation interceptor attaches, instead of the raw status string.
+func describe(err error) string {
+	st, ok := status.FromError(err)
+	if !ok || errors.Is(err, context.Canceled) {
+		return err.Error()
+	}
+
+	message := fmt.Sprintf("%s: %s", st.Code(), st.Message())
+	for _, violation := range fieldViolations(st) {
+		message += fmt.Sprintf("\n  %s: %s", violation.GetField(), violation.GetDescription())
+	}
+	return message
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"proto"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+type movieView struct {
+	Id    uint32 `json:"id" yaml:"id"`
+	Title string `json:"title" yaml:"title"`
+	Year  string `json:"year" yaml:"year"`
+}
+
+type movieListView struct {
+	Movies []movieView `json:"movies" yaml:"movies"`
+	Page   uint32      `json:"page,omitempty" yaml:"page,omitempty"`
+	Total  uint32      `json:"total" yaml:"total"`
+	More   bool        `json:"more" yaml:"more"`
+}
+
+func newMovieView(movie *proto.Movie) movieView {
+	return movieView{Id: movie.GetId(), Title: movie.GetTitle(), Year: movie.GetYear()}
+}
+
+type printer struct {
+	format string
+	out    io.Writer
+}
+
+func newPrinter(format string, out io.Writer) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return &printer{format: format, out: out}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q, expected %q, %q or %q", format, outputTable, outputJSON, outputYAML)
+	}
+}
+
+// print writes value as JSON or YAML, or calls table for the table format.
+func (p *printer) print(value any, table func(w io.Writer)) error {
+	switch p.format {
+	case outputJSON:
+		encoder := json.NewEncoder(p.out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case outputYAML:
+		encoder := yaml.NewEncoder(p.out)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(value); err != nil {
+			return err
+		}
+		return encoder.Close()
+	default:
+		writer := tabwriter.NewWriter(p.out, 0, 4, 2, ' ', 0)
+		table(writer)
+		return writer.Flush()
+	}
+}
+
+func (p *printer) movies(list movieListView) error {
+	return p.print(list, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTITLE\tYEAR")
+		for _, movie := range list.Movies {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", movie.Id, movie.Title, movie.Year)
+		}
+		if list.Page > 0 {
+			fmt.Fprintf(w, "\npage %d, %d movies in total\n", list.Page, list.Total)
+		}
+	})
+}
+
+func (p *printer) movie(movie *proto.Movie) error {
+	view := newMovieView(movie)
+	return p.print(view, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tTITLE\tYEAR")
+		fmt.Fprintf(w, "%d\t%s\t%s\n", view.Id, view.Title, view.Year)
+	})
+}
+
+func (p *printer) deleted(ids []uint32) error {
+	return p.print(struct {
+		Deleted []uint32 `json:"deleted" yaml:"deleted"`
+	}{ids}, func(w io.Writer) {
+		for _, id := range ids {
+			fmt.Fprintf(w, "deleted movie %d\n", id)
+		}
+	})
+}
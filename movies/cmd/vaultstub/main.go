@@ -0,0 +1,51 @@
+// Command vaultstub serves secrets over a Vault-compatible HTTP API from
+// a JSON file, so VAULT_ADDR can be exercised locally without a Vault
+// server:
+//
+//	{"secret/data/movies": {"MONGO_DB_PASSWORD": "..."}}
+//
+// Secrets can be rotated while it runs with a KV v2 write:
+//
+//	curl -X PUT -H "X-Vault-Token: dev" -d '{"data":{"MONGO_DB_PASSWORD":"new"}}' \
+//	  localhost:8200/v1/secret/data/movies
+//
+// Never use it outside local development.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"movies/pkg/vaultstub"
+	"net/http"
+	"os"
+)
+
+func main() {
+	addr := flag.String("addr", ":8200", "address to listen on")
+	token := flag.String("token", "dev", "token expected in X-Vault-Token; empty accepts any request")
+	file := flag.String("file", "", "JSON file mapping secret paths to their values")
+	flag.Parse()
+
+	log.SetFlags(0)
+	log.SetPrefix("vaultstub: ")
+
+	stub := vaultstub.New(*token)
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var secrets map[string]map[string]string
+		if err := json.Unmarshal(data, &secrets); err != nil {
+			log.Fatalf("%s: %v", *file, err)
+		}
+		for path, values := range secrets {
+			stub.Put(path, values)
+		}
+	}
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, stub))
+}
@@ -1,32 +1,111 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"movies/core/config"
-	"movies/core/proto"
+	"movies/core/middleware"
+	"movies/core/repository"
 	"movies/core/usecases"
-	"movies/infra/persistence/mongodb"
-	"movies/pkg/logger"
+	"movies/pkg/metrics"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"pkg/conf"
+	"pkg/logger"
+	"pkg/tlsreload"
+	"pkg/tracing"
+	"proto"
+	"syscall"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// serviceName names the movies service in traces.
+const serviceName = "movies"
+
+// NewGRPCServer wires the movies and health services behind the standard
+// interceptor chain. Run adds transport options such as mTLS credentials.
+// accessLog is kept apart from log so Run can sample it, since it writes a
+// line per call, without also dropping the usecase's error logs.
+func NewGRPCServer(
+	log *zap.Logger,
+	accessLog *zap.Logger,
+	movies repository.MoviesRepository,
+	health *usecases.HealthUsecase,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	serverOptions := append([]grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			middleware.RequestID(),
+			metrics.UnaryServerInterceptor(),
+			middleware.AccessLog(accessLog.Named("access")),
+			middleware.Validation(),
+		),
+	}, opts...)
+
+	grpcServer := grpc.NewServer(serverOptions...)
+	proto.RegisterMovieServiceServer(grpcServer, &usecases.MoviesUsecase{Repository: movies, Logger: log.Named("movies")})
+	healthpb.RegisterHealthServer(grpcServer, health)
+
+	return grpcServer
+}
+
 func Run() error {
-	cfg := config.Load()
-	log := logger.New(cfg.Env)
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, conf.ErrPrintedConfig) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
 
-	grpcServer := grpc.NewServer()
+	log, levels := logger.New(cfg.Env, logger.Options{
+		Level:          cfg.LogLevel,
+		Levels:         cfg.LogLevels,
+		File:           cfg.LogFile,
+		FileMaxSizeMB:  cfg.LogFileMaxSizeMB,
+		FileMaxBackups: cfg.LogFileMaxBackups,
+		FileMaxAgeDays: cfg.LogFileMaxAgeDays,
+	})
+	defer logger.Sync(log)
 
-	mongodb.SetLogger(log)
-	db, err := mongodb.ConnectToMongo(&cfg)
+	shutdownTracing, err := tracing.Init(serviceName, cfg.TracingExporter, cfg.OtlpEndpoint)
+	if err != nil {
+		return err
+	}
+	defer shutdownTracing(context.Background())
+
+	watchCtx, stopWatch := context.WithCancel(context.Background())
+	defer stopWatch()
+
+	var serverOptions []grpc.ServerOption
+	if cfg.GrpcTLSCertFile != "" {
+		reloader, err := tlsreload.New(cfg.GrpcTLSCertFile, cfg.GrpcTLSKeyFile, cfg.GrpcTLSCAFile)
+		if err != nil {
+			return err
+		}
+
+		go reloader.Watch(watchCtx, cfg.TLSReloadInterval, log.Named("tls"))
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+		log.Info("mTLS enabled for gRPC server")
+	}
+
+	store, err := openStorage(&cfg, log)
 	if err != nil {
 		log.Fatal(err.Error())
 	}
 
-	log.Info("Mongo connection was setup")
-	movies := mongodb.NewMoviesRepository(db, cfg.DbName, cfg.DbCollection)
-	proto.RegisterMovieServiceServer(grpcServer, &usecases.MoviesUsecase{Repository: movies})
+	health := &usecases.HealthUsecase{Ping: store.ping, Timeout: cfg.HealthCheckTimeout}
+	requestLog := logger.Sample(log, cfg.LogSamplingInitial, cfg.LogSamplingThereafter)
+	grpcServer := NewGRPCServer(log, requestLog, store.movies, health, serverOptions...)
 
 	listenPort := fmt.Sprintf(":%s", cfg.ListenPort)
 	listener, err := net.Listen("tcp", listenPort)
@@ -34,13 +113,76 @@ func Run() error {
 		log.Fatal(err.Error())
 	}
 
-	logInfo := fmt.Sprintf("Movies services running on port %s", listenPort)
-	log.Info(logInfo)
+	if cfg.AdminToken == "" {
+		log.Info("ADMIN_TOKEN is not set, /config and /log/level are disabled")
+	}
+
+	metricsServer := metrics.NewServer(cfg.MetricsPort, AdminHandlers(&cfg, levels))
+	go metrics.Serve(metricsServer, log)
 
-	grpcErr := grpcServer.Serve(listener)
-	if grpcErr != nil {
-		log.Fatal(grpcErr.Error())
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		logInfo := fmt.Sprintf("Movies services running on port %s", listenPort)
+		log.Info(logInfo)
+		serverErr <- grpcServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Info("Shutdown signal received, draining connections", zap.Duration("timeout", cfg.ShutdownTimeout))
+	health.Shutdown()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	GracefulStop(shutdownCtx, grpcServer, log)
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		log.Error("Metrics server did not shut down cleanly", zap.Error(err))
+	}
+
+	if err := store.close(shutdownCtx); err != nil {
+		log.Error("Failed to close storage", zap.Error(err))
 	}
 
+	log.Info("Movies service stopped")
 	return nil
 }
+
+// GracefulStop waits for in-flight RPCs to finish and falls back to a hard
+// stop once ctx expires, so a stuck call can't hold the shutdown forever.
+func GracefulStop(ctx context.Context, grpcServer *grpc.Server, log *zap.Logger) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		log.Warn("gRPC server did not drain in time, forcing stop")
+		grpcServer.Stop()
+		<-stopped
+	}
+}
+
+// AdminHandlers are the extra routes of the metrics port. /config is
+// redacted but still maps the deployment, so like /log/level it is only
+// served behind ADMIN_TOKEN, and neither exists without one.
+func AdminHandlers(cfg *config.Config, levels *logger.Levels) map[string]http.Handler {
+	admin := map[string]http.Handler{}
+	if cfg.AdminToken != "" {
+		admin["/config"] = metrics.RequireToken(cfg.AdminToken, cfg.Settings.Handler())
+		admin["/log/level"] = metrics.RequireToken(cfg.AdminToken, levels.Handler())
+	}
+	return admin
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"context"
+	"movies/core/config"
+	"movies/seed"
+
+	"go.uber.org/zap"
+)
+
+// seedStorage applies the configured seed file, or the embedded one, to a
+// freshly opened store and logs what changed.
+func seedStorage(cfg *config.Config, store seed.Store, log *zap.Logger) error {
+	mode, err := seed.ParseMode(cfg.SeedMode)
+	if err != nil || mode == seed.ModeOff {
+		return err
+	}
+
+	reader, err := seed.Open(cfg.SeedPath, seed.Format(cfg.SeedFormat))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	source := cfg.SeedPath
+	if source == "" {
+		source = "embedded"
+	}
+
+	report, err := seed.Apply(context.Background(), store, reader, seed.Options{Mode: mode, DryRun: cfg.SeedDryRun})
+	if err != nil {
+		return err
+	}
+
+	if report.Skipped {
+		log.Debug("Storage already has movies, skipping seed", zap.String("mode", string(mode)))
+		return nil
+	}
+
+	log.Info("Seed applied",
+		zap.String("source", source),
+		zap.String("mode", string(mode)),
+		zap.Bool("dry_run", report.DryRun),
+		zap.Int("read", report.Read),
+		zap.Int("inserted", report.Inserted),
+		zap.Int("updated", report.Updated),
+		zap.Int("unchanged", report.Unchanged),
+	)
+	return nil
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"movies/core/config"
+	"movies/core/repository"
+	"movies/infra/persistence/bolt"
+	"movies/infra/persistence/mongodb"
+	"movies/infra/persistence/postgres"
+	"movies/seed"
+
+	"go.uber.org/zap"
+)
+
+const (
+	StorageMongoDB  = "mongodb"
+	StorageBolt     = "bolt"
+	StoragePostgres = "postgres"
+)
+
+// storage bundles the selected repository with what the service needs to
+// seed it, report its health and release it on shutdown.
+type storage struct {
+	movies repository.MoviesRepository
+	seed   seed.Store
+	ping   func(ctx context.Context) error
+	close  func(ctx context.Context) error
+}
+
+func openStorage(cfg *config.Config, log *zap.Logger) (*storage, error) {
+	var store *storage
+	switch cfg.StorageBackend {
+	case StorageMongoDB:
+		mongodb.SetLogger(log.Named("mongodb"))
+		db, err := mongodb.ConnectToMongo(cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("Mongo connection was setup")
+		rotator := mongodb.NewRotator(*cfg, db)
+		watchCtx, stopWatch := context.WithCancel(context.Background())
+		if cfg.SecretsReloadInterval > 0 {
+			go rotator.Watch(watchCtx, cfg.SecretsReloadInterval, log.Named("mongodb"))
+		}
+
+		store = &storage{
+			movies: rotator.Repository(),
+			seed:   mongodb.NewSeedStore(db.Database(cfg.DbName).Collection(cfg.DbCollection)),
+			ping:   rotator.Ping,
+			close: func(ctx context.Context) error {
+				stopWatch()
+				return rotator.Disconnect(ctx)
+			},
+		}
+	case StorageBolt:
+		db, err := bolt.Open(cfg.BoltPath)
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("Embedded bolt storage opened", zap.String("path", cfg.BoltPath))
+		store = &storage{
+			movies: bolt.NewMoviesRepository(db),
+			seed:   bolt.NewSeedStore(db),
+			ping:   bolt.Ping(db),
+			close:  func(context.Context) error { return db.Close() },
+		}
+	case StoragePostgres:
+		db, err := postgres.Open(cfg.PostgresDSN, log.Named("postgres"))
+		if err != nil {
+			return nil, err
+		}
+
+		log.Info("Postgres connection was setup")
+		store = &storage{
+			movies: postgres.NewMoviesRepository(db),
+			seed:   postgres.NewSeedStore(db),
+			ping:   postgres.Ping(db),
+			close:  func(context.Context) error { return db.Close() },
+		}
+	default:
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q, expected %q, %q or %q",
+			cfg.StorageBackend, StorageMongoDB, StorageBolt, StoragePostgres)
+	}
+
+	if err := seedStorage(cfg, store.seed, log.Named("seed")); err != nil {
+		store.close(context.Background())
+		return nil, err
+	}
+
+	return store, nil
+}
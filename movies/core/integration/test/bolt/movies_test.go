@@ -0,0 +1,74 @@
+package bolt
+
+import (
+	"context"
+	"movies/infra/persistence/bolt"
+	"movies/seed"
+	"path/filepath"
+	"proto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	bbolt "go.etcd.io/bbolt"
+)
+
+const seedJSON = `[
+	{"id": 8, "title": "Edison Kinetoscopic Record of a Sneeze (1894)", "year": "1894"},
+	{"id": 10, "title": "La sortie des usines Lumière", "year": "1895"},
+	{"id": 12, "title": "The Arrival of a Train", "year": "1895"}
+]`
+
+func applySeed(t *testing.T, db *bbolt.DB, data string, format seed.Format, opts seed.Options) seed.Report {
+	reader, err := seed.NewReader(strings.NewReader(data), format)
+	require.NoError(t, err)
+
+	report, err := seed.Apply(context.Background(), bolt.NewSeedStore(db), reader, opts)
+	require.NoError(t, err)
+	return report
+}
+
+func openSeeded(t *testing.T) *bolt.MoviesRepositoryImpl {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "movies.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	applySeed(t, db, seedJSON, seed.FormatJSON, seed.Options{Mode: seed.ModeIfEmpty})
+	return bolt.NewMoviesRepository(db)
+}
+
+func TestSeedOnFirstOpen(t *testing.T) {
+	repo := openSeeded(t)
+	ctx := context.Background()
+
+	movies, total, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Equal(t, uint32(12), movies[0].Id)
+
+	created, err := repo.Create(ctx, &proto.Movie{Title: "New", Year: "2024"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(13), created.Id, "ids continue after the seeded ones")
+}
+
+func TestSeedOnlyWhenEmpty(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "movies.db")
+
+	db, err := bolt.Open(dbPath)
+	require.NoError(t, err)
+	applySeed(t, db, seedJSON, seed.FormatJSON, seed.Options{Mode: seed.ModeIfEmpty})
+	require.NoError(t, bolt.NewMoviesRepository(db).Delete(context.Background(), 8))
+	require.NoError(t, db.Close())
+
+	db, err = bolt.Open(dbPath)
+	require.NoError(t, err)
+	defer db.Close()
+
+	report := applySeed(t, db, seedJSON, seed.FormatJSON, seed.Options{Mode: seed.ModeIfEmpty})
+	assert.True(t, report.Skipped)
+
+	_, total, err := bolt.NewMoviesRepository(db).FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), total)
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"movies/core/config"
+	"movies/pkg/vaultstub"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"pkg/conf"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type secrets struct {
+	User     string `env:"USER_NAME"`
+	Password string `env:"PASSWORD" secret:"true"`
+	Token    string `env:"TOKEN" secret:"true"`
+}
+
+func TestSecretsFromFile(t *testing.T) {
+	path := writeFile(t, "password", "from-file\n")
+
+	var cfg secrets
+	all, err := conf.Load(&cfg, conf.Options{
+		Args:      []string{"-token", "from-flag"},
+		LookupEnv: lookup(map[string]string{"PASSWORD_FILE": path, "TOKEN": "from-env"}),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "from-file", cfg.Password)
+	assert.Equal(t, "secret-file", sourceOf(all, "PASSWORD").Source)
+	assert.Equal(t, "from-flag", cfg.Token)
+	assert.Equal(t, conf.SourceFlag, sourceOf(all, "TOKEN").Source)
+}
+
+func TestSecretsProviderOrder(t *testing.T) {
+	path := writeFile(t, "password", "from-file")
+	env := lookup(map[string]string{"PASSWORD": "from-env", "PASSWORD_FILE": path})
+
+	var cfg secrets
+	_, err := conf.Load(&cfg, conf.Options{LookupEnv: env})
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.Password)
+
+	_, err = conf.Load(&cfg, conf.Options{
+		LookupEnv: env,
+		Secrets:   []conf.SecretProvider{conf.FileSecrets{LookupEnv: env}, conf.EnvSecrets{LookupEnv: env}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", cfg.Password)
+}
+
+func TestSecretsMissingFile(t *testing.T) {
+	var cfg secrets
+	_, err := conf.Load(&cfg, conf.Options{
+		LookupEnv: lookup(map[string]string{"PASSWORD_FILE": "/nonexistent/password"}),
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "PASSWORD: secret-file: PASSWORD_FILE:")
+}
+
+func TestSecretsFromVault(t *testing.T) {
+	stub := vaultstub.New("dev")
+	stub.Put("secret/data/movies", map[string]string{"PASSWORD": "from-vault"})
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+
+	tokenFile := writeFile(t, "vault-token", "dev\n")
+	env := lookup(map[string]string{
+		"VAULT_ADDR":        server.URL,
+		"VAULT_TOKEN_FILE":  tokenFile,
+		"VAULT_SECRET_PATH": "secret/data/movies",
+	})
+
+	var cfg secrets
+	all, err := conf.Load(&cfg, conf.Options{LookupEnv: env})
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", cfg.Password)
+	assert.Equal(t, "vault", sourceOf(all, "PASSWORD").Source)
+	assert.Empty(t, cfg.Token)
+
+	providers, err := conf.SecretsFromEnv(env)
+	require.NoError(t, err)
+
+	stub.Put("secret/data/movies", map[string]string{"PASSWORD": "rotated"})
+	changed, err := conf.RefreshSecrets(context.Background(), &cfg, all, providers)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "rotated", cfg.Password)
+
+	changed, err = conf.RefreshSecrets(context.Background(), &cfg, all, providers)
+	require.NoError(t, err)
+	assert.False(t, changed)
+}
+
+func TestSecretsVaultReadOncePerPass(t *testing.T) {
+	stub := vaultstub.New("dev")
+	stub.Put("secret/data/movies", map[string]string{"PASSWORD": "from-vault", "TOKEN": "t0ken"})
+
+	var reads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		reads.Add(1)
+		stub.ServeHTTP(writer, request)
+	}))
+	t.Cleanup(server.Close)
+
+	env := lookup(map[string]string{
+		"VAULT_ADDR":        server.URL,
+		"VAULT_TOKEN":       "dev",
+		"VAULT_SECRET_PATH": "secret/data/movies",
+	})
+
+	var cfg secrets
+	all, err := conf.Load(&cfg, conf.Options{LookupEnv: env})
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", cfg.Password)
+	assert.Equal(t, "t0ken", cfg.Token)
+	assert.Equal(t, int32(1), reads.Load(), "two secret fields, one read")
+
+	providers, err := conf.SecretsFromEnv(env)
+	require.NoError(t, err)
+
+	_, err = conf.RefreshSecrets(context.Background(), &cfg, all, providers)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), reads.Load(), "each refresh reads the path again, once")
+}
+
+func TestSecretsVaultErrors(t *testing.T) {
+	server := httptest.NewServer(vaultstub.New("dev"))
+	t.Cleanup(server.Close)
+
+	_, err := conf.SecretsFromEnv(lookup(map[string]string{"VAULT_ADDR": server.URL}))
+	assert.ErrorContains(t, err, "VAULT_SECRET_PATH is required")
+
+	var cfg secrets
+	_, err = conf.Load(&cfg, conf.Options{LookupEnv: lookup(map[string]string{
+		"VAULT_ADDR":        server.URL,
+		"VAULT_TOKEN":       "wrong",
+		"VAULT_SECRET_PATH": "secret/data/movies",
+	})})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "403 Forbidden")
+}
+
+func TestMoviesReloadSecrets(t *testing.T) {
+	path := writeFile(t, "mongo_password", "first")
+	t.Setenv("ENV", "test")
+	t.Setenv("MONGO_DB_USER", "movies")
+	t.Setenv("MONGO_DB_PASSWORD_FILE", path)
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "first", cfg.DBPassword)
+
+	_, changed, err := cfg.ReloadSecrets(context.Background())
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	require.NoError(t, os.WriteFile(path, []byte("second"), 0o600))
+	next, changed, err := cfg.ReloadSecrets(context.Background())
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "second", next.DBPassword)
+	assert.Equal(t, "first", cfg.DBPassword, "the loaded config is left alone")
+}
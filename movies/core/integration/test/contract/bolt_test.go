@@ -0,0 +1,20 @@
+package contract
+
+import (
+	"movies/core/repository"
+	"movies/infra/persistence/bolt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoviesRepositoryBolt_Contract(t *testing.T) {
+	RunMoviesRepository(t, func(t *testing.T) repository.MoviesRepository {
+		db, err := bolt.Open(filepath.Join(t.TempDir(), "movies.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+
+		return bolt.NewMoviesRepository(db)
+	})
+}
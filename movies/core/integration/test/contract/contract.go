@@ -0,0 +1,228 @@
+package contract
+
+import (
+	"context"
+	"fmt"
+	"movies/core/repository"
+	"movies/core/util"
+	"proto"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns an empty repository. It is called once per subtest so
+// every case starts from a clean state.
+type Factory func(t *testing.T) repository.MoviesRepository
+
+// RunMoviesRepository checks the behaviour every repository.MoviesRepository
+// adapter must share, so adapters can be swapped without surprises.
+func RunMoviesRepository(t *testing.T, newRepository Factory) {
+	cases := []struct {
+		name string
+		run  func(t *testing.T, repo repository.MoviesRepository)
+	}{
+		{"create allocates increasing ids", testCreateAllocatesIDs},
+		{"find by id returns the stored movie", testFindByID},
+		{"find by id reports not found", testFindByIDNotFound},
+		{"find all orders newest first", testFindAllOrdering},
+		{"find all paginates", testFindAllPagination},
+		{"find all past the last page is empty", testFindAllOutOfRange},
+		{"find all on empty repository", testFindAllEmpty},
+		{"delete removes only the target", testDelete},
+		{"delete reports not found", testDeleteNotFound},
+		{"cancelled context is an error", testCancelledContext},
+		{"concurrent creates get unique ids", testConcurrentCreates},
+	}
+
+	for _, testCase := range cases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.run(t, newRepository(t))
+		})
+	}
+}
+
+func seed(t *testing.T, repo repository.MoviesRepository, count int) []*proto.Movie {
+	t.Helper()
+
+	movies := make([]*proto.Movie, 0, count)
+	for i := range count {
+		movie, err := repo.Create(context.Background(), &proto.Movie{
+			Title: fmt.Sprintf("Movie %d", i+1),
+			Year:  fmt.Sprintf("%d", 2000+i),
+		})
+		require.NoError(t, err)
+		movies = append(movies, movie)
+	}
+
+	return movies
+}
+
+func titles(movies []*proto.Movie) []string {
+	result := make([]string, 0, len(movies))
+	for _, movie := range movies {
+		result = append(result, movie.Title)
+	}
+	return result
+}
+
+func testCreateAllocatesIDs(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 3)
+
+	assert.NotZero(t, movies[0].Id)
+	assert.Greater(t, movies[1].Id, movies[0].Id)
+	assert.Greater(t, movies[2].Id, movies[1].Id)
+	assert.Equal(t, "Movie 1", movies[0].Title)
+	assert.Equal(t, "2000", movies[0].Year)
+}
+
+func testFindByID(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 2)
+
+	found, err := repo.FindById(context.Background(), &proto.MovieIdRequest{Id: movies[1].Id})
+
+	require.NoError(t, err)
+	assert.Equal(t, movies[1].Id, found.Id)
+	assert.Equal(t, "Movie 2", found.Title)
+	assert.Equal(t, "2001", found.Year)
+}
+
+func testFindByIDNotFound(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 1)
+
+	found, err := repo.FindById(context.Background(), &proto.MovieIdRequest{Id: movies[0].Id + 1000})
+
+	assert.ErrorIs(t, err, util.ErrMovieNotFound)
+	assert.Nil(t, found)
+}
+
+func testFindAllOrdering(t *testing.T, repo repository.MoviesRepository) {
+	seed(t, repo, 3)
+
+	movies, total, err := repo.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Equal(t, []string{"Movie 3", "Movie 2", "Movie 1"}, titles(movies))
+}
+
+func testFindAllPagination(t *testing.T, repo repository.MoviesRepository) {
+	seed(t, repo, 5)
+	ctx := context.Background()
+
+	first, total, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(5), total)
+	assert.Equal(t, []string{"Movie 5", "Movie 4"}, titles(first))
+
+	second, _, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 2, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Movie 3", "Movie 2"}, titles(second))
+
+	last, _, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 3, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Movie 1"}, titles(last))
+}
+
+func testFindAllOutOfRange(t *testing.T, repo repository.MoviesRepository) {
+	seed(t, repo, 3)
+
+	movies, total, err := repo.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 10, Limit: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, uint32(3), total)
+	assert.Empty(t, movies)
+}
+
+func testFindAllEmpty(t *testing.T, repo repository.MoviesRepository) {
+	movies, total, err := repo.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 10})
+
+	require.NoError(t, err)
+	assert.Zero(t, total)
+	assert.Empty(t, movies)
+}
+
+func testDelete(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 2)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Delete(ctx, movies[0].Id))
+
+	_, err := repo.FindById(ctx, &proto.MovieIdRequest{Id: movies[0].Id})
+	assert.ErrorIs(t, err, util.ErrMovieNotFound)
+
+	other, err := repo.FindById(ctx, &proto.MovieIdRequest{Id: movies[1].Id})
+	require.NoError(t, err)
+	assert.Equal(t, "Movie 2", other.Title)
+
+	_, total, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), total)
+}
+
+func testDeleteNotFound(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 1)
+
+	err := repo.Delete(context.Background(), movies[0].Id+1000)
+
+	assert.ErrorIs(t, err, util.ErrMovieNotFound)
+}
+
+// testCancelledContext makes sure failures are reported as such and not
+// disguised as a missing movie.
+func testCancelledContext(t *testing.T, repo repository.MoviesRepository) {
+	movies := seed(t, repo, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	assert.Error(t, err)
+
+	_, err = repo.FindById(ctx, &proto.MovieIdRequest{Id: movies[0].Id})
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, util.ErrMovieNotFound)
+
+	_, err = repo.Create(ctx, &proto.Movie{Title: "Cancelled", Year: "2000"})
+	assert.Error(t, err)
+
+	err = repo.Delete(ctx, movies[0].Id)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, util.ErrMovieNotFound)
+}
+
+func testConcurrentCreates(t *testing.T, repo repository.MoviesRepository) {
+	const workers = 20
+
+	var group sync.WaitGroup
+	ids := make(chan uint32, workers)
+	for i := range workers {
+		group.Go(func() {
+			movie, err := repo.Create(context.Background(), &proto.Movie{
+				Title: fmt.Sprintf("Concurrent %d", i),
+				Year:  "2020",
+			})
+			if assert.NoError(t, err) {
+				ids <- movie.Id
+			}
+
+			_, _, err = repo.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 5})
+			assert.NoError(t, err)
+		})
+	}
+	group.Wait()
+	close(ids)
+
+	seen := make(map[uint32]bool)
+	for id := range ids {
+		assert.False(t, seen[id], "id %d allocated twice", id)
+		seen[id] = true
+	}
+	assert.Len(t, seen, workers)
+
+	_, total, err := repo.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(workers), total)
+}
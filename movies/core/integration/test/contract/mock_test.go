@@ -0,0 +1,13 @@
+package contract
+
+import (
+	"movies/core/repository"
+	"movies/infra/persistence/mock"
+	"testing"
+)
+
+func TestMoviesRepositoryMock_Contract(t *testing.T) {
+	RunMoviesRepository(t, func(t *testing.T) repository.MoviesRepository {
+		return mock.NewMoviesRepositoryMock()
+	})
+}
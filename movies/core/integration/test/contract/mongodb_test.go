@@ -0,0 +1,48 @@
+//go:build mongodb
+
+package contract
+
+import (
+	"context"
+	"fmt"
+	"movies/core/repository"
+	"movies/infra/persistence/mongodb"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Run with a local mongod:
+//
+//	MONGO_TEST_URI=mongodb://localhost:27017 go test -tags mongodb ./core/integration/test/contract
+func TestMoviesRepositoryMongo_Contract(t *testing.T) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil), "mongod not reachable at %s", uri)
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	dbName := fmt.Sprintf("movies_contract_%d", time.Now().UnixNano())
+	t.Cleanup(func() { client.Database(dbName).Drop(context.Background()) })
+
+	RunMoviesRepository(t, func(t *testing.T) repository.MoviesRepository {
+		collection := strings.NewReplacer(" ", "_", "/", "_").Replace(t.Name())
+
+		// Ids come from a package-wide counter; restart it so every case
+		// sees the same numbering as the other adapters.
+		require.NoError(t, mongodb.ResetCurrentID(context.Background(), client.Database(dbName).Collection(collection)))
+		return mongodb.NewMoviesRepository(client, dbName, collection)
+	})
+}
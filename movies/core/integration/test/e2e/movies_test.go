@@ -42,7 +42,10 @@ func shouldNotBeError(test *testing.T, res, route string) {
 }
 
 func TestMain(m *testing.M) {
-	cfg := config.Load()
+	cfg, err := config.Load(nil)
+	if err != nil {
+		panic(err)
+	}
 	baseUrl = fmt.Sprintf("http://localhost:%s", cfg.ApiPort)
 
 	os.Exit(m.Run())
@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"movies/core/usecases"
+	"proto"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var moviesService = proto.MovieService_ServiceDesc.ServiceName
+
+// watchStream collects what Watch sends; only Send and Context are used.
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan healthpb.HealthCheckResponse_ServingStatus
+}
+
+func (stream *watchStream) Context() context.Context {
+	return stream.ctx
+}
+
+func (stream *watchStream) Send(response *healthpb.HealthCheckResponse) error {
+	stream.sent <- response.Status
+	return nil
+}
+
+func newUsecase(down *atomic.Bool) *usecases.HealthUsecase {
+	return &usecases.HealthUsecase{
+		Ping: func(context.Context) error {
+			if down.Load() {
+				return errors.New("no primary")
+			}
+			return nil
+		},
+		Timeout:       time.Second,
+		WatchInterval: 10 * time.Millisecond,
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var down atomic.Bool
+	service := newUsecase(&down)
+
+	for _, name := range []string{"", moviesService} {
+		response, err := service.Check(context.Background(), &healthpb.HealthCheckRequest{Service: name})
+		require.NoError(t, err)
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status, name)
+	}
+
+	down.Store(true)
+	response, err := service.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status, "a failed ping means not serving")
+
+	_, err = service.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "other.Service"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCheckAfterShutdown(t *testing.T) {
+	var down atomic.Bool
+	service := newUsecase(&down)
+	service.Shutdown()
+
+	response, err := service.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status)
+}
+
+func TestList(t *testing.T) {
+	var down atomic.Bool
+	service := newUsecase(&down)
+
+	response, err := service.List(context.Background(), &healthpb.HealthListRequest{})
+	require.NoError(t, err)
+	require.Len(t, response.Statuses, 2)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Statuses[""].Status)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Statuses[moviesService].Status)
+}
+
+func watch(service *usecases.HealthUsecase, name string) (*watchStream, context.CancelFunc, <-chan error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &watchStream{ctx: ctx, sent: make(chan healthpb.HealthCheckResponse_ServingStatus, 10)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- service.Watch(&healthpb.HealthCheckRequest{Service: name}, stream)
+	}()
+	return stream, cancel, done
+}
+
+func next(t *testing.T, stream *watchStream) healthpb.HealthCheckResponse_ServingStatus {
+	select {
+	case sent := <-stream.sent:
+		return sent
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch sent nothing")
+		return healthpb.HealthCheckResponse_UNKNOWN
+	}
+}
+
+func TestWatchSendsChanges(t *testing.T) {
+	var down atomic.Bool
+	service := newUsecase(&down)
+	stream, cancel, done := watch(service, moviesService)
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, next(t, stream))
+
+	down.Store(true)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, next(t, stream))
+
+	down.Store(false)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, next(t, stream))
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-done))
+}
+
+func TestWatchUnknownServiceKeepsStreaming(t *testing.T) {
+	var down atomic.Bool
+	stream, cancel, done := watch(newUsecase(&down), "other.Service")
+
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVICE_UNKNOWN, next(t, stream))
+
+	select {
+	case err := <-done:
+		t.Fatalf("Watch ended the stream for an unknown service: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-done))
+}
@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"pkg/logger"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newFileLogger logs to a temporary file so tests can read back what got
+// through the level filter.
+func newFileLogger(t *testing.T, opts logger.Options) (*zap.Logger, *logger.Levels, func() []string) {
+	opts.File = filepath.Join(t.TempDir(), "movies.log")
+	opts.FileMaxSizeMB = 1
+
+	log, levels := logger.New("production", opts)
+	read := func() []string {
+		file, err := os.Open(opts.File)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		defer file.Close()
+
+		var messages []string
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var entry struct {
+				Logger  string `json:"logger"`
+				Message string `json:"message"`
+			}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+			messages = append(messages, strings.TrimPrefix(entry.Logger+":"+entry.Message, ":"))
+		}
+		return messages
+	}
+	return log, levels, read
+}
+
+func TestNamedLoggerLevels(t *testing.T) {
+	log, levels, read := newFileLogger(t, logger.Options{Level: "warn", Levels: "mongodb=debug"})
+
+	log.Info("root info")
+	log.Warn("root warn")
+	log.Named("mongodb").Debug("mongo debug")
+	log.Named("mongodb").Named("migrate").Debug("migrate debug")
+	log.Named("access").Info("access info")
+
+	assert.Equal(t, []string{"root warn", "mongodb:mongo debug", "mongodb.migrate:migrate debug"}, read())
+
+	levels.Set("mongodb.migrate", zapcore.ErrorLevel)
+	levels.Root().SetLevel(zapcore.InfoLevel)
+	log.Named("mongodb").Named("migrate").Warn("migrate warn")
+	log.Named("access").Info("access info")
+
+	assert.Equal(t, []string{"root warn", "mongodb:mongo debug", "mongodb.migrate:migrate debug", "access:access info"}, read())
+	assert.Equal(t, zapcore.ErrorLevel, levels.Level("mongodb.migrate"))
+	assert.Equal(t, zapcore.DebugLevel, levels.Level("mongodb.other"))
+	assert.Equal(t, zapcore.InfoLevel, levels.Level("grpc"))
+}
+
+func TestParseLevels(t *testing.T) {
+	parsed, err := logger.ParseLevels(" mongodb=debug, access=warn ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]zapcore.Level{"mongodb": zapcore.DebugLevel, "access": zapcore.WarnLevel}, parsed)
+
+	_, err = logger.ParseLevels("mongodb")
+	assert.ErrorContains(t, err, "expected name=level")
+	_, err = logger.ParseLevels("mongodb=loud")
+	assert.Error(t, err)
+}
+
+func TestLevelsHandler(t *testing.T) {
+	_, levels, _ := newFileLogger(t, logger.Options{})
+	handler := levels.Handler()
+
+	request := func(method, target, body string) (int, string) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return recorder.Code, strings.TrimSpace(recorder.Body.String())
+	}
+
+	code, body := request(http.MethodPut, "/log/level", `{"level":"error"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"level":"error"}`, body)
+	assert.Equal(t, zapcore.ErrorLevel, levels.Root().Level())
+
+	code, body = request(http.MethodPut, "/log/level?logger=mongodb", `{"level":"debug"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.JSONEq(t, `{"level":"debug"}`, body)
+
+	_, body = request(http.MethodGet, "/log/level?logger=mongodb.migrate", "")
+	assert.JSONEq(t, `{"level":"debug"}`, body)
+
+	_, body = request(http.MethodDelete, "/log/level?logger=mongodb", "")
+	assert.JSONEq(t, `{"level":"error"}`, body)
+
+	code, _ = request(http.MethodPut, "/log/level?logger=mongodb", `{"level":"loud"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+	code, _ = request(http.MethodPost, "/log/level?logger=mongodb", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, code)
+}
+
+func TestSampleKeepsWarnings(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	log := logger.Sample(zap.New(core), 3, 1000)
+
+	for i := 0; i < 50; i++ {
+		log.With(zap.Int("request", i)).Info("rpc")
+	}
+	for i := 0; i < 10; i++ {
+		log.Error("rpc")
+	}
+
+	assert.Equal(t, 3, logs.FilterLevelExact(zapcore.InfoLevel).Len())
+	assert.Equal(t, 10, logs.FilterLevelExact(zapcore.ErrorLevel).Len())
+
+	unsampled := logger.Sample(zap.New(core), 3, 0)
+	for i := 0; i < 10; i++ {
+		unsampled.Info("unsampled")
+	}
+	assert.Equal(t, 10, logs.FilterMessage("unsampled").Len())
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"movies/core/app"
+	"movies/core/usecases"
+	"movies/infra/persistence/mock"
+	"net"
+	"proto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type brokenRepository struct {
+	*mock.MoviesRepositoryMock
+}
+
+func (brokenRepository) FindById(context.Context, *proto.MovieIdRequest) (*proto.Movie, error) {
+	return nil, errors.New("disk on fire")
+}
+
+// Run passes a sampled logger as accessLog; only the access log lines may
+// end up there, the usecase keeps writing to the service log.
+func TestGRPCServerKeepsUsecaseLogsOffTheAccessLog(t *testing.T) {
+	serviceCore, serviceLogs := observer.New(zapcore.DebugLevel)
+	accessCore, accessLogs := observer.New(zapcore.DebugLevel)
+
+	repo := brokenRepository{mock.NewMoviesRepositoryMock().(*mock.MoviesRepositoryMock)}
+	health := &usecases.HealthUsecase{Ping: func(context.Context) error { return nil }, Timeout: time.Second}
+	grpcServer := app.NewGRPCServer(zap.New(serviceCore), zap.New(accessCore), repo, health)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = proto.NewMovieServiceClient(conn).GetMovie(context.Background(), &proto.MovieIdRequest{Id: 1})
+	require.Error(t, err)
+
+	assert.Equal(t, 1, named(serviceLogs, "movies"))
+	assert.Zero(t, named(serviceLogs, "access"))
+	assert.Equal(t, 1, named(accessLogs, "access"))
+	assert.Zero(t, named(accessLogs, "movies"))
+}
+
+func named(logs *observer.ObservedLogs, name string) int {
+	return logs.Filter(func(entry observer.LoggedEntry) bool { return entry.LoggerName == name }).Len()
+}
@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"movies/pkg/metrics"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func scrape(t *testing.T) string {
+	recorder := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestUnaryServerInterceptorRecordsCode(t *testing.T) {
+	interceptor := metrics.UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Metrics/Unary"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.NotFound, "missing")
+	})
+	assert.Equal(t, codes.NotFound, status.Code(err), "the handler's error is passed through")
+
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	require.NoError(t, err)
+
+	body := scrape(t)
+	assert.Contains(t, body, `movies_grpc_server_handling_seconds_count{code="NotFound",method="/test.Metrics/Unary"} 1`)
+	assert.Contains(t, body, `movies_grpc_server_handling_seconds_count{code="OK",method="/test.Metrics/Unary"} 1`)
+}
+
+func TestStorageOperationStatus(t *testing.T) {
+	start := time.Now()
+	metrics.ObserveMongoOperation("test_mongo", start, nil)
+	metrics.ObserveMongoOperation("test_mongo", start, errors.New("boom"))
+	metrics.ObserveBoltOperation("test_bolt", start, errors.New("boom"))
+	metrics.ObservePostgresOperation("test_postgres", start, nil)
+
+	body := scrape(t)
+	assert.Contains(t, body, `movies_mongo_operation_duration_seconds_count{operation="test_mongo",status="ok"} 1`)
+	assert.Contains(t, body, `movies_mongo_operation_duration_seconds_count{operation="test_mongo",status="error"} 1`)
+	assert.Contains(t, body, `movies_bolt_operation_duration_seconds_count{operation="test_bolt",status="error"} 1`)
+	assert.Contains(t, body, `movies_postgres_operation_duration_seconds_count{operation="test_postgres",status="ok"} 1`)
+}
+
+func TestServerRoutes(t *testing.T) {
+	server := metrics.NewServer("9100", map[string]http.Handler{
+		"/extra": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusTeapot) }),
+	})
+	assert.Equal(t, ":9100", server.Addr)
+
+	recorder := httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "go_goroutines")
+
+	recorder = httptest.NewRecorder()
+	server.Handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/extra", nil))
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+}
+
+func TestRequireToken(t *testing.T) {
+	handler := metrics.RequireToken("s3cret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for header, want := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"s3cret":        http.StatusUnauthorized,
+		"Bearer s3cret": http.StatusOK,
+	} {
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			request.Header.Set("Authorization", header)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		assert.Equal(t, want, recorder.Code, header)
+		if want == http.StatusUnauthorized {
+			assert.Equal(t, "Bearer", recorder.Header().Get("WWW-Authenticate"))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"context"
+	"movies/core/middleware"
+	"pkg/requestid"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var info = &grpc.UnaryServerInfo{FullMethod: "/movies.Movies/GetMovie"}
+
+// seenID runs the interceptor and returns the ID the handler got.
+func seenID(t *testing.T, ctx context.Context) string {
+	var id string
+	_, err := middleware.RequestID()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+		id = requestid.FromContext(ctx)
+		return nil, nil
+	})
+	require.NoError(t, err)
+	return id
+}
+
+func TestRequestIDUsesForwardedID(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "from-gateway"))
+	assert.Equal(t, "from-gateway", seenID(t, ctx))
+}
+
+func TestRequestIDGeneratesMissingOrInvalidID(t *testing.T) {
+	contexts := map[string]context.Context{
+		"no metadata": context.Background(),
+		"no id":       metadata.NewIncomingContext(context.Background(), metadata.Pairs("other", "x")),
+		"invalid id":  metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "has space")),
+	}
+
+	for name, ctx := range contexts {
+		id := seenID(t, ctx)
+		assert.True(t, requestid.IsValid(id), name)
+		assert.NotEqual(t, "has space", id, name)
+	}
+}
+
+func TestAccessLogCarriesRequestID(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	chain := func(ctx context.Context, err error) {
+		middleware.RequestID()(ctx, nil, info, func(ctx context.Context, req any) (any, error) {
+			return middleware.AccessLog(zap.New(core))(ctx, req, info, func(context.Context, any) (any, error) {
+				return nil, err
+			})
+		})
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.MetadataKey, "joined-1"))
+	chain(ctx, nil)
+	chain(ctx, status.Error(codes.Internal, "boom"))
+
+	entries := logs.All()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "joined-1", entries[0].ContextMap()["request_id"])
+	assert.Equal(t, "OK", entries[0].ContextMap()["code"])
+	assert.Equal(t, zapcore.ErrorLevel, entries[1].Level)
+	assert.Equal(t, "Internal", entries[1].ContextMap()["code"])
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"movies/core/middleware"
+	"movies/core/util"
+	"proto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidation(t *testing.T) {
+	cases := map[string]struct {
+		req        any
+		violations map[string]string
+	}{
+		"valid movie":      {&proto.Movie{Title: "Up", Year: "2009"}, nil},
+		"valid page":       {&proto.GetMoviesRequest{Page: 1, Limit: 10}, nil},
+		"not a proto":      {"plain value", nil},
+		"message no rules": {&proto.Empty{}, nil},
+		"empty movie": {&proto.Movie{}, map[string]string{
+			"title": "is required",
+			"year":  "is required",
+		}},
+		"zero id": {&proto.MovieIdRequest{}, map[string]string{"id": "must be greater than 0"}},
+		"bad paging": {&proto.GetMoviesRequest{Limit: 500}, map[string]string{
+			"page":  "must be greater than 0",
+			"limit": "must be between 1 and 100",
+		}},
+	}
+
+	for name, tc := range cases {
+		called := false
+		_, err := middleware.Validation()(context.Background(), tc.req, info, func(ctx context.Context, req any) (any, error) {
+			called = true
+			return nil, nil
+		})
+
+		if tc.violations == nil {
+			assert.NoError(t, err, name)
+			assert.True(t, called, name)
+			continue
+		}
+
+		assert.False(t, called, "%s: invalid requests never reach the usecase", name)
+
+		st := status.Convert(err)
+		assert.Equal(t, codes.InvalidArgument, st.Code(), name)
+
+		got := map[string]string{}
+		var reason string
+		for _, detail := range st.Details() {
+			switch detail := detail.(type) {
+			case *errdetails.ErrorInfo:
+				reason = detail.Reason
+			case *errdetails.BadRequest:
+				for _, violation := range detail.FieldViolations {
+					got[violation.Field] = violation.Description
+				}
+			}
+		}
+		assert.Equal(t, util.ReasonValidationFailed, reason, name)
+		assert.Equal(t, tc.violations, got, name)
+	}
+}
+
+func TestValidationPassesHandlerResult(t *testing.T) {
+	movie := &proto.Movie{Id: 1, Title: "Up", Year: "2009"}
+	resp, err := middleware.Validation()(context.Background(), movie, info, func(ctx context.Context, req any) (any, error) {
+		return req, status.Error(codes.AlreadyExists, "exists")
+	})
+
+	require.Same(t, movie, resp)
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
@@ -1,8 +1,9 @@
 package mock
 
 import (
-	"movies/core/proto"
+	"context"
 	"movies/infra/persistence/mock"
+	"proto"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +11,7 @@ import (
 )
 
 func TestMoviesRepositoryMock_FindAll(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := mock.NewMoviesRepositoryMock()
 	mock := mockRepo.(*mock.MoviesRepositoryMock)
 
@@ -26,7 +28,7 @@ func TestMoviesRepositoryMock_FindAll(t *testing.T) {
 			Limit: 2,
 		}
 
-		result, total, err := mockRepo.FindAll(req)
+		result, total, err := mockRepo.FindAll(ctx, req)
 
 		require.NoError(t, err)
 		assert.Equal(t, uint32(3), total)
@@ -42,7 +44,7 @@ func TestMoviesRepositoryMock_FindAll(t *testing.T) {
 			Limit: 2,
 		}
 
-		result, total, err := mockRepo.FindAll(req)
+		result, total, err := mockRepo.FindAll(ctx, req)
 
 		require.NoError(t, err)
 		assert.Equal(t, uint32(3), total)
@@ -51,6 +53,7 @@ func TestMoviesRepositoryMock_FindAll(t *testing.T) {
 }
 
 func TestMoviesRepositoryMock_FindById(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := mock.NewMoviesRepositoryMock()
 	mock := mockRepo.(*mock.MoviesRepositoryMock)
 
@@ -59,7 +62,7 @@ func TestMoviesRepositoryMock_FindById(t *testing.T) {
 	})
 
 	t.Run("should return movie when found", func(t *testing.T) {
-		movie, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 1})
+		movie, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 1})
 
 		require.NoError(t, err)
 		assert.Equal(t, "The Matrix", movie.Title)
@@ -67,7 +70,7 @@ func TestMoviesRepositoryMock_FindById(t *testing.T) {
 	})
 
 	t.Run("should return error when not found", func(t *testing.T) {
-		movie, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 999})
+		movie, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 999})
 
 		assert.Error(t, err)
 		assert.Nil(t, movie)
@@ -75,6 +78,7 @@ func TestMoviesRepositoryMock_FindById(t *testing.T) {
 }
 
 func TestMoviesRepositoryMock_Create(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := mock.NewMoviesRepositoryMock()
 
 	t.Run("should create movie with auto-increment ID", func(t *testing.T) {
@@ -83,14 +87,14 @@ func TestMoviesRepositoryMock_Create(t *testing.T) {
 			Year:  "2008",
 		}
 
-		created, err := mockRepo.Create(newMovie)
+		created, err := mockRepo.Create(ctx, newMovie)
 
 		require.NoError(t, err)
 		assert.Equal(t, uint32(1), created.Id)
 		assert.Equal(t, "The Dark Knight", created.Title)
 		assert.Equal(t, "2008", created.Year)
 
-		found, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 1})
+		found, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 1})
 		require.NoError(t, err)
 		assert.Equal(t, created, found)
 	})
@@ -99,17 +103,18 @@ func TestMoviesRepositoryMock_Create(t *testing.T) {
 		movie2 := &proto.Movie{Title: "Movie 2", Year: "2020"}
 		movie3 := &proto.Movie{Title: "Movie 3", Year: "2021"}
 
-		created2, err := mockRepo.Create(movie2)
+		created2, err := mockRepo.Create(ctx, movie2)
 		require.NoError(t, err)
 		assert.Equal(t, uint32(2), created2.Id)
 
-		created3, err := mockRepo.Create(movie3)
+		created3, err := mockRepo.Create(ctx, movie3)
 		require.NoError(t, err)
 		assert.Equal(t, uint32(3), created3.Id)
 	})
 }
 
 func TestMoviesRepositoryMock_Delete(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := mock.NewMoviesRepositoryMock()
 	mock := mockRepo.(*mock.MoviesRepositoryMock)
 
@@ -119,36 +124,37 @@ func TestMoviesRepositoryMock_Delete(t *testing.T) {
 	})
 
 	t.Run("should delete existing movie", func(t *testing.T) {
-		err := mockRepo.Delete(1)
+		err := mockRepo.Delete(ctx, 1)
 		require.NoError(t, err)
 
-		movie, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 1})
+		movie, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 1})
 		assert.Error(t, err)
 		assert.Nil(t, movie)
 
-		otherMovie, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 2})
+		otherMovie, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 2})
 		require.NoError(t, err)
 		assert.Equal(t, "Inception", otherMovie.Title)
 	})
 
 	t.Run("should return error for non-existent movie", func(t *testing.T) {
-		err := mockRepo.Delete(999)
+		err := mockRepo.Delete(ctx, 999)
 		assert.Error(t, err)
 	})
 }
 
 func TestMoviesRepositoryMock_ClearAndSeed(t *testing.T) {
+	ctx := context.Background()
 	mockRepo := mock.NewMoviesRepositoryMock()
 	mock := mockRepo.(*mock.MoviesRepositoryMock)
 
-	mockRepo.Create(&proto.Movie{Title: "Movie 1", Year: "2000"})
-	mockRepo.Create(&proto.Movie{Title: "Movie 2", Year: "2001"})
+	mockRepo.Create(ctx, &proto.Movie{Title: "Movie 1", Year: "2000"})
+	mockRepo.Create(ctx, &proto.Movie{Title: "Movie 2", Year: "2001"})
 
 	assert.Equal(t, uint32(3), mock.GetNextID())
 
 	mock.Clear()
 
-	movies, total, err := mockRepo.FindAll(&proto.GetMoviesRequest{Page: 1, Limit: 10})
+	movies, total, err := mockRepo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
 	require.NoError(t, err)
 	assert.Equal(t, uint32(0), total)
 	assert.Len(t, movies, 0)
@@ -160,8 +166,30 @@ func TestMoviesRepositoryMock_ClearAndSeed(t *testing.T) {
 	}
 	mock.Seed(seedMovies)
 
-	movie, err := mockRepo.FindById(&proto.MovieIdRequest{Id: 10})
+	movie, err := mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 10})
 	require.NoError(t, err)
 	assert.Equal(t, "Seeded 1", movie.Title)
 	assert.Equal(t, uint32(21), mock.GetNextID())
 }
+
+func TestMoviesRepositoryMock_CancelledContext(t *testing.T) {
+	mockRepo := mock.NewMoviesRepositoryMock()
+	mockRepo.(*mock.MoviesRepositoryMock).Seed([]*proto.Movie{
+		{Id: 1, Title: "The Matrix", Year: "1999"},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := mockRepo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = mockRepo.FindById(ctx, &proto.MovieIdRequest{Id: 1})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	_, err = mockRepo.Create(ctx, &proto.Movie{Title: "Movie", Year: "2000"})
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = mockRepo.Delete(ctx, 1)
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,46 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"movies/core/util"
+	"movies/infra/persistence/mongodb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// TranslateError needs no server, so unlike the rest of the package this
+// file runs without the mongodb build tag.
+func TestTranslateError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"no documents":     {mongo.ErrNoDocuments, util.ErrMovieNotFound},
+		"wrapped no docs":  {fmt.Errorf("find: %w", mongo.ErrNoDocuments), util.ErrMovieNotFound},
+		"duplicate key":    {mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, util.ErrMovieAlreadyExists},
+		"server selection": {topology.ServerSelectionError{Wrapped: errors.New("no reachable servers")}, util.ErrRepositoryUnavailable},
+		"network error":    {mongo.CommandError{Labels: []string{"NetworkError"}}, util.ErrRepositoryUnavailable},
+		"deadline":         {context.DeadlineExceeded, util.ErrRepositoryTimeout},
+		"max time expired": {mongo.CommandError{Code: 50}, util.ErrRepositoryTimeout},
+	}
+
+	for name, tc := range cases {
+		err := mongodb.TranslateError(tc.err)
+		assert.ErrorIs(t, err, tc.want, name)
+		if tc.want != util.ErrMovieNotFound {
+			assert.Contains(t, err.Error(), tc.err.Error(), "%s: the driver error stays in the chain", name)
+		}
+	}
+}
+
+func TestTranslateErrorPassesThroughOthers(t *testing.T) {
+	assert.NoError(t, mongodb.TranslateError(nil))
+
+	other := errors.New("boom")
+	assert.Same(t, other, mongodb.TranslateError(other))
+}
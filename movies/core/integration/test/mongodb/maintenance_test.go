@@ -0,0 +1,158 @@
+//go:build mongodb
+
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"movies/core/util"
+	"movies/infra/persistence/mongodb"
+	"proto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestDumpAndRestore(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	source := db.Collection("source")
+	target := db.Collection("target")
+
+	_, err := source.InsertMany(ctx, []interface{}{
+		bson.M{"id": 1, "title": "The Matrix", "year": 1999},
+		bson.M{"id": 2, "title": "Inception", "year": 2010},
+	})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	dumped, err := mongodb.Dump(ctx, source, &archive)
+	require.NoError(t, err)
+	assert.Equal(t, 2, dumped)
+
+	restored, err := mongodb.Restore(ctx, target, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+
+	var original, copied []bson.M
+	cursor, err := source.Find(ctx, bson.M{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &original))
+	cursor, err = target.Find(ctx, bson.M{})
+	require.NoError(t, err)
+	require.NoError(t, cursor.All(ctx, &copied))
+	assert.Equal(t, original, copied, "documents come back unchanged, _id included")
+
+	_, err = mongodb.Restore(ctx, target, bytes.NewReader(archive.Bytes()[:archive.Len()-3]))
+	assert.Error(t, err, "a truncated archive is rejected")
+
+	oversized := binary.LittleEndian.AppendUint32(nil, 1<<30)
+	_, err = mongodb.Restore(ctx, target, bytes.NewReader(oversized))
+	assert.ErrorContains(t, err, "invalid document length", "a length past the BSON limit is rejected before allocating")
+}
+
+func TestRestoreReplacing(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	source := db.Collection("source")
+	target := db.Collection(collectionName)
+
+	_, err := source.InsertMany(ctx, []interface{}{
+		bson.M{"id": 1, "title": "The Matrix", "year": 1999},
+		bson.M{"id": 2, "title": "Inception", "year": 2010},
+	})
+	require.NoError(t, err)
+	_, err = target.InsertOne(ctx, bson.M{"id": 9, "title": "Old", "year": 1990})
+	require.NoError(t, err)
+
+	var archive bytes.Buffer
+	_, err = mongodb.Dump(ctx, source, &archive)
+	require.NoError(t, err)
+
+	_, err = mongodb.RestoreReplacing(ctx, target, bytes.NewReader(archive.Bytes()[:archive.Len()-3]))
+	require.Error(t, err, "a truncated archive is rejected")
+
+	count, err := target.CountDocuments(ctx, bson.M{"id": 9})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count, "a failed restore leaves the collection alone")
+
+	restored, err := mongodb.RestoreReplacing(ctx, target, bytes.NewReader(archive.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+
+	count, err = target.CountDocuments(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "the old documents are gone")
+
+	_, err = target.InsertOne(ctx, bson.M{"id": 2})
+	assert.Error(t, err, "the unique id index comes with the restored collection")
+
+	names, err := db.ListCollectionNames(ctx, bson.M{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"source", collectionName}, names, "no staging collection is left behind")
+}
+
+func TestRebuildIndexesAndMaxID(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	collection := db.Collection(collectionName)
+
+	maxID, err := mongodb.MaxID(ctx, collection)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(0), maxID)
+
+	_, err = collection.InsertMany(ctx, []interface{}{bson.M{"id": 3}, bson.M{"id": 7}})
+	require.NoError(t, err)
+	_, err = collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: bson.D{{Key: "title", Value: 1}}})
+	require.NoError(t, err)
+
+	dropped, err := mongodb.RebuildIndexes(ctx, collection)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"title_1"}, dropped)
+
+	dropped, err = mongodb.RebuildIndexes(ctx, collection)
+	require.NoError(t, err)
+	assert.Empty(t, dropped, "the expected indexes are kept, not dropped and recreated")
+
+	_, err = collection.InsertOne(ctx, bson.M{"id": 7})
+	assert.Error(t, err, "the unique id index is back")
+
+	maxID, err = mongodb.MaxID(ctx, collection)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(7), maxID)
+}
+
+func TestForEachAndUpdateMovie(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	collection := db.Collection(collectionName)
+
+	_, err := collection.InsertMany(ctx, []interface{}{
+		bson.M{"id": 2, "title": " Padded ", "year": "1999"},
+		bson.M{"id": 1, "title": "Fine", "year": 2001},
+	})
+	require.NoError(t, err)
+
+	var seen []*proto.Movie
+	require.NoError(t, mongodb.ForEachMovie(ctx, collection, func(movie *proto.Movie) error {
+		seen = append(seen, movie)
+		return nil
+	}))
+	require.Len(t, seen, 2)
+	assert.Equal(t, uint32(1), seen[0].Id)
+	assert.Equal(t, "2001", seen[0].Year)
+
+	require.NoError(t, mongodb.UpdateMovie(ctx, collection, &proto.Movie{Id: 2, Title: "Padded", Year: "1999"}))
+
+	var updated bson.M
+	require.NoError(t, collection.FindOne(ctx, bson.M{"id": 2}).Decode(&updated))
+	assert.Equal(t, "Padded", updated["title"])
+	assert.Equal(t, int32(1999), updated["year"])
+
+	err = mongodb.UpdateMovie(ctx, collection, &proto.Movie{Id: 99, Title: "x", Year: "2000"})
+	assert.ErrorIs(t, err, util.ErrMovieNotFound)
+}
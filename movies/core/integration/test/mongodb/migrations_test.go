@@ -0,0 +1,140 @@
+//go:build mongodb
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"movies/infra/persistence/mongodb"
+	"os"
+	"proto"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const collectionName = "movies"
+
+// Run with a local mongod:
+//
+//	MONGO_TEST_URI=mongodb://localhost:27017 go test -tags mongodb ./core/integration/test/mongodb
+func setupDatabase(t *testing.T) *mongo.Database {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	require.NoError(t, err)
+	require.NoError(t, client.Ping(ctx, nil), "mongod not reachable at %s", uri)
+	t.Cleanup(func() { client.Disconnect(context.Background()) })
+
+	db := client.Database(fmt.Sprintf("movies_migrations_%d", time.Now().UnixNano()))
+	t.Cleanup(func() { db.Drop(context.Background()) })
+
+	return db
+}
+
+func TestMigrate_UpgradesLegacyDocuments(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	legacy := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	_, err := db.Collection(collectionName).InsertMany(ctx, []interface{}{
+		bson.M{"id": 1, "title": "Seeded", "year": "1894"},
+		bson.M{"id": 2, "title": "Legacy", "year": " 1999 ", "createdAt": legacy},
+		bson.M{"id": 3, "title": "Unknown year", "year": "n/a"},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, mongodb.Migrate(ctx, db, collectionName, zap.NewNop()))
+
+	var seeded, withLegacyTimestamp, unknown bson.M
+	require.NoError(t, db.Collection(collectionName).FindOne(ctx, bson.M{"id": 1}).Decode(&seeded))
+	require.NoError(t, db.Collection(collectionName).FindOne(ctx, bson.M{"id": 2}).Decode(&withLegacyTimestamp))
+	require.NoError(t, db.Collection(collectionName).FindOne(ctx, bson.M{"id": 3}).Decode(&unknown))
+
+	assert.Equal(t, int32(1894), seeded["year"])
+	assert.Contains(t, seeded, "created_at")
+	assert.Contains(t, seeded, "updated_at")
+
+	assert.Equal(t, int32(1999), withLegacyTimestamp["year"])
+	assert.NotContains(t, withLegacyTimestamp, "createdAt")
+	assert.Equal(t, legacy, withLegacyTimestamp["created_at"].(primitive.DateTime).Time().UTC())
+
+	assert.Equal(t, "n/a", unknown["year"])
+
+	// The repository still reads both shapes back as proto strings.
+	repo := mongodb.NewMoviesRepository(db.Client(), db.Name(), collectionName)
+	movie, err := repo.FindById(ctx, &proto.MovieIdRequest{Id: 1})
+	require.NoError(t, err)
+	assert.Equal(t, "1894", movie.Year)
+
+	movie, err = repo.FindById(ctx, &proto.MovieIdRequest{Id: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "n/a", movie.Year)
+}
+
+func TestMigrate_RecordsAndSkipsApplied(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+
+	statuses, err := mongodb.MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	for _, status := range statuses {
+		assert.True(t, status.AppliedAt.IsZero(), "migration %d should be pending", status.Version)
+	}
+
+	require.NoError(t, mongodb.Migrate(ctx, db, collectionName, zap.NewNop()))
+	require.NoError(t, mongodb.Migrate(ctx, db, collectionName, zap.NewNop()))
+
+	statuses, err = mongodb.MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.False(t, status.AppliedAt.IsZero(), "migration %d should be applied", status.Version)
+	}
+
+	count, err := db.Collection("_migrations").CountDocuments(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(statuses)), count)
+}
+
+func TestMigrate_ConcurrentReplicas(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- mongodb.Migrate(ctx, db, collectionName, zap.NewNop())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		require.NoError(t, err)
+	}
+
+	statuses, err := mongodb.MigrationStatuses(ctx, db)
+	require.NoError(t, err)
+
+	count, err := db.Collection("_migrations").CountDocuments(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(statuses)), count)
+}
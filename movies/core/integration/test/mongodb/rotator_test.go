@@ -0,0 +1,91 @@
+//go:build mongodb
+
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"movies/core/config"
+	"movies/infra/persistence/mongodb"
+	"os"
+	"path/filepath"
+	"proto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestRotator_ReconnectsWithRotatedPassword(t *testing.T) {
+	db := setupDatabase(t)
+	ctx := context.Background()
+	admin := db.Client().Database("admin")
+
+	user := fmt.Sprintf("rotator_%d", time.Now().UnixNano())
+	require.NoError(t, admin.RunCommand(ctx, bson.D{
+		{Key: "createUser", Value: user},
+		{Key: "pwd", Value: "first"},
+		{Key: "roles", Value: bson.A{bson.M{"role": "readWrite", "db": db.Name()}}},
+	}).Err())
+	t.Cleanup(func() { admin.RunCommand(context.Background(), bson.D{{Key: "dropUser", Value: user}}) })
+
+	passwordFile := filepath.Join(t.TempDir(), "mongo_password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("first\n"), 0o600))
+	tokenFile := filepath.Join(t.TempDir(), "admin_token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token-1\n"), 0o600))
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		uri = "mongodb://localhost:27017"
+	}
+	t.Setenv("ENV", "test")
+	t.Setenv("MONGO_DB_URI", uri)
+	t.Setenv("MONGO_DB", db.Name())
+	t.Setenv("MONGO_DB_USER", user)
+	t.Setenv("MONGO_DB_PASSWORD_FILE", passwordFile)
+	t.Setenv("ADMIN_TOKEN_FILE", tokenFile)
+
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
+	require.Equal(t, "first", cfg.DBPassword)
+
+	client, err := mongodb.Connect(&cfg)
+	require.NoError(t, err)
+
+	rotator := mongodb.NewRotator(cfg, client)
+	t.Cleanup(func() { rotator.Disconnect(context.Background()) })
+	require.NoError(t, rotator.Ping(ctx))
+
+	rotated, err := rotator.Rotate(ctx)
+	require.NoError(t, err)
+	assert.False(t, rotated, "unchanged secrets must not reconnect")
+
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token-2\n"), 0o600))
+	rotated, err = rotator.Rotate(ctx)
+	require.NoError(t, err)
+	assert.False(t, rotated, "only the Mongo credentials trigger a reconnect")
+
+	// A password that doesn't work yet keeps the current connection.
+	require.NoError(t, os.WriteFile(passwordFile, []byte("second\n"), 0o600))
+	_, err = rotator.Rotate(ctx)
+	require.Error(t, err)
+	require.NoError(t, rotator.Ping(ctx))
+
+	require.NoError(t, admin.RunCommand(ctx, bson.D{
+		{Key: "updateUser", Value: user},
+		{Key: "pwd", Value: "second"},
+	}).Err())
+
+	rotated, err = rotator.Rotate(ctx)
+	require.NoError(t, err)
+	assert.True(t, rotated)
+
+	movies := rotator.Repository()
+	created, err := movies.Create(ctx, &proto.Movie{Id: 1, Title: "Rotated", Year: "2025"})
+	require.NoError(t, err)
+	found, err := movies.FindById(ctx, &proto.MovieIdRequest{Id: created.Id})
+	require.NoError(t, err)
+	assert.Equal(t, "Rotated", found.Title)
+}
@@ -0,0 +1,190 @@
+package moviesctl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"movies/core/app"
+	"movies/core/usecases"
+	"movies/infra/persistence/mock"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"proto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+var binary string
+
+// TestMain builds moviesctl once; each test runs it against its own
+// in-process movies server backed by the mock repository.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "moviesctl")
+	if err != nil {
+		panic(err)
+	}
+
+	binary = filepath.Join(dir, "moviesctl")
+	build := exec.Command("go", "build", "-o", binary, "movies/cmd/moviesctl")
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		panic(err)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func startServer(t *testing.T, movies ...*proto.Movie) (string, *mock.MoviesRepositoryMock) {
+	repository := mock.NewMoviesRepositoryMock().(*mock.MoviesRepositoryMock)
+	repository.Seed(movies)
+
+	health := &usecases.HealthUsecase{Ping: func(context.Context) error { return nil }}
+	server := app.NewGRPCServer(zap.NewNop(), zap.NewNop(), repository, health)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), repository
+}
+
+type result struct {
+	stdout string
+	stderr string
+	err    error
+}
+
+func run(t *testing.T, addr string, stdin string, args ...string) result {
+	command := exec.Command(binary, append([]string{"-addr", addr}, args...)...)
+	command.Dir = t.TempDir()
+	command.Env = append(os.Environ(), "GRPC_TLS_CERT_FILE=", "GRPC_TLS_KEY_FILE=", "GRPC_TLS_CA_FILE=")
+	command.Stdin = strings.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	command.Stdout, command.Stderr = &stdout, &stderr
+	err := command.Run()
+
+	return result{stdout: stdout.String(), stderr: stderr.String(), err: err}
+}
+
+var seeded = []*proto.Movie{
+	{Id: 1, Title: "The Matrix", Year: "1999"},
+	{Id: 2, Title: "Inception", Year: "2010"},
+	{Id: 3, Title: "Interstellar", Year: "2014"},
+}
+
+func TestGetOutputs(t *testing.T) {
+	addr, _ := startServer(t, seeded...)
+
+	table := run(t, addr, "", "get", "2")
+	require.NoError(t, table.err, table.stderr)
+	assert.Contains(t, table.stdout, "ID  TITLE      YEAR")
+	assert.Contains(t, table.stdout, "2   Inception  2010")
+
+	asJSON := run(t, addr, "", "-o", "json", "get", "2")
+	require.NoError(t, asJSON.err, asJSON.stderr)
+	assert.JSONEq(t, `{"id": 2, "title": "Inception", "year": "2010"}`, asJSON.stdout)
+
+	asYAML := run(t, addr, "", "-o", "yaml", "get", "2")
+	require.NoError(t, asYAML.err, asYAML.stderr)
+	var movie map[string]any
+	require.NoError(t, yaml.Unmarshal([]byte(asYAML.stdout), &movie))
+	assert.Equal(t, map[string]any{"id": 2, "title": "Inception", "year": "2010"}, movie)
+}
+
+func TestGetNotFound(t *testing.T) {
+	addr, _ := startServer(t)
+
+	missing := run(t, addr, "", "get", "42")
+	require.Error(t, missing.err)
+	assert.Contains(t, missing.stderr, "NotFound")
+}
+
+func TestListAllPages(t *testing.T) {
+	addr, _ := startServer(t, seeded...)
+
+	list := run(t, addr, "", "-o", "json", "list", "-limit", "2", "-all")
+	require.NoError(t, list.err, list.stderr)
+
+	var response struct {
+		Movies []struct{ Id uint32 } `json:"movies"`
+		Total  uint32                `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(list.stdout), &response))
+	assert.Equal(t, uint32(3), response.Total)
+	require.Len(t, response.Movies, 3)
+	assert.Equal(t, uint32(3), response.Movies[0].Id)
+}
+
+func TestCreateAndDelete(t *testing.T) {
+	addr, repository := startServer(t, seeded...)
+
+	created := run(t, addr, "", "-o", "json", "create", "-title", "Dune", "-year", "2021")
+	require.NoError(t, created.err, created.stderr)
+	assert.JSONEq(t, `{"id": 4, "title": "Dune", "year": "2021"}`, created.stdout)
+
+	deleted := run(t, addr, "", "delete", "1", "4")
+	require.NoError(t, deleted.err, deleted.stderr)
+	assert.Equal(t, "deleted movie 1\ndeleted movie 4\n", deleted.stdout)
+	assert.Equal(t, uint32(5), repository.GetNextID())
+
+	_, err := repository.FindById(context.Background(), &proto.MovieIdRequest{Id: 1})
+	assert.Error(t, err)
+}
+
+func TestCreateReportsViolations(t *testing.T) {
+	addr, _ := startServer(t)
+
+	invalid := run(t, addr, "", "create", "-title", "Dune", "-year", "21")
+	require.Error(t, invalid.err)
+	assert.Contains(t, invalid.stderr, "InvalidArgument")
+	assert.Contains(t, invalid.stderr, "year:")
+}
+
+func TestExportImportRoundTrip(t *testing.T) {
+	source, _ := startServer(t, seeded...)
+	target, repository := startServer(t)
+
+	for _, format := range []string{"json", "ndjson", "csv"} {
+		t.Run(format, func(t *testing.T) {
+			repository.Clear()
+
+			exported := run(t, source, "", "export", "-format", format)
+			require.NoError(t, exported.err, exported.stderr)
+
+			imported := run(t, target, exported.stdout, "-o", "json", "import", "-format", format, "-")
+			require.NoError(t, imported.err, imported.stderr)
+
+			movies, total, err := repository.FindAll(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 10})
+			require.NoError(t, err)
+			assert.Equal(t, uint32(3), total)
+
+			var titles []string
+			for _, movie := range movies {
+				titles = append(titles, movie.Title)
+			}
+			assert.ElementsMatch(t, []string{"The Matrix", "Inception", "Interstellar"}, titles)
+		})
+	}
+}
+
+func TestCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh"} {
+		script := run(t, "127.0.0.1:1", "", "completion", shell)
+		require.NoError(t, script.err, script.stderr)
+		assert.Contains(t, script.stdout, "_moviesctl")
+		assert.Contains(t, script.stdout, "export")
+		assert.Contains(t, script.stdout, "-limit")
+	}
+}
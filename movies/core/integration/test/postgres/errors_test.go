@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"movies/core/util"
+	"movies/infra/persistence/postgres"
+	"net"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// TranslateError needs no database, so unlike the rest of the package
+// this file runs without the postgres build tag.
+func TestTranslateError(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want error
+	}{
+		"no rows":          {sql.ErrNoRows, util.ErrMovieNotFound},
+		"wrapped no rows":  {fmt.Errorf("scan: %w", sql.ErrNoRows), util.ErrMovieNotFound},
+		"unique violation": {&pgconn.PgError{Code: "23505"}, util.ErrMovieAlreadyExists},
+		"deadline":         {context.DeadlineExceeded, util.ErrRepositoryTimeout},
+		"conn done":        {sql.ErrConnDone, util.ErrRepositoryUnavailable},
+		"network error":    {&net.OpError{Op: "dial", Err: errors.New("connection refused")}, util.ErrRepositoryUnavailable},
+	}
+
+	for name, tc := range cases {
+		err := postgres.TranslateError(tc.err)
+		assert.ErrorIs(t, err, tc.want, name)
+		if tc.want != util.ErrMovieNotFound {
+			assert.Contains(t, err.Error(), tc.err.Error(), "%s: the driver error stays in the chain", name)
+		}
+	}
+}
+
+func TestTranslateErrorPassesThroughOthers(t *testing.T) {
+	assert.NoError(t, postgres.TranslateError(nil))
+
+	other := &pgconn.PgError{Code: "23503"}
+	assert.Same(t, other, postgres.TranslateError(other))
+}
@@ -0,0 +1,196 @@
+package seed
+
+import (
+	"context"
+	"io"
+	"movies/infra/persistence/bolt"
+	"movies/seed"
+	"os"
+	"path/filepath"
+	"proto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readAll(t *testing.T, reader *seed.Reader) []seed.Movie {
+	var movies []seed.Movie
+	for {
+		movie, err := reader.Next()
+		if err == io.EOF {
+			return movies
+		}
+		require.NoError(t, err)
+		movies = append(movies, movie)
+	}
+}
+
+func newReader(t *testing.T, data string, format seed.Format) *seed.Reader {
+	reader, err := seed.NewReader(strings.NewReader(data), format)
+	require.NoError(t, err)
+	return reader
+}
+
+func openStore(t *testing.T) (*bolt.SeedStore, *bolt.MoviesRepositoryImpl) {
+	db, err := bolt.Open(filepath.Join(t.TempDir(), "movies.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return bolt.NewSeedStore(db), bolt.NewMoviesRepository(db)
+}
+
+var expected = []seed.Movie{
+	{Id: 8, Title: "Sneeze", Year: "1894"},
+	{Id: 10, Title: "La sortie, des usines", Year: "1895"},
+}
+
+func TestReaderFormats(t *testing.T) {
+	tests := []struct {
+		name   string
+		format seed.Format
+		data   string
+	}{
+		{"json array", seed.FormatJSON, `[{"id": 8, "title": "Sneeze", "year": "1894"}, {"id": 10, "title": "La sortie, des usines", "year": 1895}]`},
+		{"ndjson", seed.FormatNDJSON, "{\"id\": 8, \"title\": \"Sneeze\", \"year\": \"1894\"}\n{\"id\": 10, \"title\": \"La sortie, des usines\", \"year\": \"1895\"}\n"},
+		{"csv", seed.FormatCSV, "year,title,id,extra\n1894,Sneeze,8,x\n1895,\"La sortie, des usines\",10,y\n"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, expected, readAll(t, newReader(t, test.data, test.format)))
+		})
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	_, err := seed.NewReader(strings.NewReader("id,name\n1,x\n"), seed.FormatCSV)
+	assert.ErrorContains(t, err, "title and year")
+
+	_, err = seed.NewReader(strings.NewReader(`{"id": 1}`), seed.FormatJSON)
+	assert.Error(t, err, "a JSON seed must be an array")
+
+	reader := newReader(t, `[{"id": 1, "title": "ok", "year": "2000"}, {"title": "no id", "year": "2000"}]`, seed.FormatJSON)
+	_, err = reader.Next()
+	require.NoError(t, err)
+	_, err = reader.Next()
+	assert.ErrorContains(t, err, "seed record 2: missing id")
+
+	reader = newReader(t, "id,title,year\nabc,x,2000\n", seed.FormatCSV)
+	_, err = reader.Next()
+	assert.ErrorContains(t, err, "seed record 1")
+
+	reader = newReader(t, "title,year\nx,2000\n", seed.FormatCSV)
+	_, err = reader.Next()
+	assert.ErrorContains(t, err, "missing id")
+
+	reader = newReader(t, "title,year\nx,2000\n", seed.FormatCSV)
+	reader.IDOptional = true
+	assert.Equal(t, []seed.Movie{{Title: "x", Year: "2000"}}, readAll(t, reader))
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, seed.FormatJSON, seed.DetectFormat("movies.json"))
+	assert.Equal(t, seed.FormatNDJSON, seed.DetectFormat("movies.ndjson"))
+	assert.Equal(t, seed.FormatNDJSON, seed.DetectFormat("movies.jsonl"))
+	assert.Equal(t, seed.FormatCSV, seed.DetectFormat("/data/Movies.CSV"))
+}
+
+func TestOpenFileAndEmbedded(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "movies.csv")
+	require.NoError(t, os.WriteFile(path, []byte("id,title,year\n8,Sneeze,1894\n"), 0o644))
+
+	reader, err := seed.Open(path, "")
+	require.NoError(t, err)
+	assert.Equal(t, expected[:1], readAll(t, reader))
+	require.NoError(t, reader.Close())
+
+	reader, err = seed.Open("", "")
+	require.NoError(t, err)
+	defer reader.Close()
+
+	first, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, uint32(8), first.Id, "the embedded seed is the shipped movies.json")
+}
+
+func TestApplyIfEmpty(t *testing.T) {
+	store, repo := openStore(t)
+	ctx := context.Background()
+	opts := seed.Options{Mode: seed.ModeIfEmpty}
+
+	report, err := seed.Apply(ctx, store, newReader(t, `[{"id": 8, "title": "Sneeze", "year": "1894"}]`, seed.FormatJSON), opts)
+	require.NoError(t, err)
+	assert.Equal(t, seed.Report{Read: 1, Inserted: 1}, report)
+
+	report, err = seed.Apply(ctx, store, newReader(t, `[{"id": 9, "title": "Other", "year": "1895"}]`, seed.FormatJSON), opts)
+	require.NoError(t, err)
+	assert.True(t, report.Skipped)
+
+	_, total, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), total)
+}
+
+func TestApplyUpsert(t *testing.T) {
+	store, repo := openStore(t)
+	ctx := context.Background()
+	initial := "id,title,year\n8,Sneeze,1894\n10,Sortie,1895\n"
+	updated := "id,title,year\n8,Sneeze,1894\n10,La sortie,1896\n12,Arrival,1896\n"
+
+	_, err := seed.Apply(ctx, store, newReader(t, initial, seed.FormatCSV), seed.Options{Mode: seed.ModeUpsert})
+	require.NoError(t, err)
+
+	report, err := seed.Apply(ctx, store, newReader(t, updated, seed.FormatCSV), seed.Options{Mode: seed.ModeUpsert, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, seed.Report{DryRun: true, Read: 3, Inserted: 1, Updated: 1, Unchanged: 1}, report)
+
+	_, total, err := repo.FindAll(ctx, &proto.GetMoviesRequest{Page: 1, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), total, "a dry run writes nothing")
+
+	report, err = seed.Apply(ctx, store, newReader(t, updated, seed.FormatCSV), seed.Options{Mode: seed.ModeUpsert})
+	require.NoError(t, err)
+	assert.Equal(t, seed.Report{Read: 3, Inserted: 1, Updated: 1, Unchanged: 1}, report)
+
+	movie, err := repo.FindById(ctx, &proto.MovieIdRequest{Id: 10})
+	require.NoError(t, err)
+	assert.Equal(t, "La sortie", movie.Title)
+	assert.Equal(t, "1896", movie.Year)
+
+	created, err := repo.Create(ctx, &proto.Movie{Title: "New", Year: "2024"})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(13), created.Id)
+}
+
+func TestParseMode(t *testing.T) {
+	mode, err := seed.ParseMode("upsert")
+	require.NoError(t, err)
+	assert.Equal(t, seed.ModeUpsert, mode)
+
+	_, err = seed.ParseMode("always")
+	assert.Error(t, err)
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, format := range []seed.Format{seed.FormatJSON, seed.FormatNDJSON, seed.FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buffer strings.Builder
+			writer, err := seed.NewWriter(&buffer, format)
+			require.NoError(t, err)
+			for _, movie := range expected {
+				require.NoError(t, writer.Write(movie))
+			}
+			require.NoError(t, writer.Close())
+
+			assert.Equal(t, expected, readAll(t, newReader(t, buffer.String(), format)))
+		})
+	}
+
+	var empty strings.Builder
+	writer, err := seed.NewWriter(&empty, seed.FormatJSON)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	assert.Empty(t, readAll(t, newReader(t, empty.String(), seed.FormatJSON)))
+}
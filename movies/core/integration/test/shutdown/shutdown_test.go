@@ -0,0 +1,147 @@
+package shutdown
+
+import (
+	"context"
+	"movies/core/app"
+	"movies/core/usecases"
+	"movies/infra/persistence/mock"
+	"net"
+	"proto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// blockingRepository holds FindById until release is closed or the call
+// is cancelled, standing in for a slow query that is still running when
+// the signal arrives.
+type blockingRepository struct {
+	*mock.MoviesRepositoryMock
+	started chan struct{}
+	release chan struct{}
+}
+
+func (repo *blockingRepository) FindById(ctx context.Context, req *proto.MovieIdRequest) (*proto.Movie, error) {
+	close(repo.started)
+	select {
+	case <-repo.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return repo.MoviesRepositoryMock.FindById(ctx, req)
+}
+
+type server struct {
+	grpc   *grpc.Server
+	health *usecases.HealthUsecase
+	repo   *blockingRepository
+	conn   *grpc.ClientConn
+}
+
+func start(t *testing.T) *server {
+	repo := &blockingRepository{
+		MoviesRepositoryMock: mock.NewMoviesRepositoryMock().(*mock.MoviesRepositoryMock),
+		started:              make(chan struct{}),
+		release:              make(chan struct{}),
+	}
+	repo.Seed([]*proto.Movie{{Id: 1, Title: "The Matrix", Year: "1999"}})
+
+	health := &usecases.HealthUsecase{Ping: func(context.Context) error { return nil }, Timeout: time.Second}
+	grpcServer := app.NewGRPCServer(zap.NewNop(), zap.NewNop(), repo, health)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return &server{grpc: grpcServer, health: health, repo: repo, conn: conn}
+}
+
+// inFlight starts a GetMovie call and returns once the handler is running.
+func (server *server) inFlight(t *testing.T) <-chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := proto.NewMovieServiceClient(server.conn).GetMovie(context.Background(), &proto.MovieIdRequest{Id: 1})
+		result <- err
+	}()
+
+	select {
+	case <-server.repo.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the call never reached the repository")
+	}
+	return result
+}
+
+func stopAsync(server *server, timeout time.Duration) <-chan struct{} {
+	stopped := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		app.GracefulStop(ctx, server.grpc, zap.NewNop())
+		close(stopped)
+	}()
+	return stopped
+}
+
+func TestGracefulStopDrainsInFlightCalls(t *testing.T) {
+	server := start(t)
+	result := server.inFlight(t)
+
+	stopped := stopAsync(server, 10*time.Second)
+	select {
+	case <-stopped:
+		t.Fatal("GracefulStop returned while a call was still running")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(server.repo.release)
+	require.NoError(t, <-result, "the in-flight call completes")
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("GracefulStop did not return after the call finished")
+	}
+}
+
+func TestGracefulStopForcesStuckCalls(t *testing.T) {
+	server := start(t)
+	defer close(server.repo.release)
+	result := server.inFlight(t)
+
+	select {
+	case <-stopAsync(server, 100*time.Millisecond):
+	case <-time.After(5 * time.Second):
+		t.Fatal("GracefulStop did not give up after its deadline")
+	}
+
+	assert.Equal(t, codes.Unavailable, status.Code(<-result), "the stuck call is cut off")
+}
+
+func TestHealthReportsNotServingOnShutdown(t *testing.T) {
+	server := start(t)
+	client := healthpb.NewHealthClient(server.conn)
+
+	response, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, response.Status)
+
+	server.health.Shutdown()
+
+	response, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, response.Status, "checks fail before the server stops accepting calls")
+}
@@ -0,0 +1,277 @@
+package tlsreload
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"pkg/tlsreload"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue returns a leaf for name, valid for both server and client auth,
+// as PEM certificate and key.
+func (ca *authority) issue(t *testing.T, name string) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+type files struct {
+	cert, key, ca string
+}
+
+// write puts a leaf from ca and ca's bundle on disk, bumping the mtimes so
+// a poll notices the change even within the filesystem's resolution.
+func write(t *testing.T, dir string, ca *authority, name string) files {
+	cert, key := ca.issue(t, name)
+	paths := files{
+		cert: filepath.Join(dir, "tls.crt"),
+		key:  filepath.Join(dir, "tls.key"),
+		ca:   filepath.Join(dir, "ca.crt"),
+	}
+
+	modTime := time.Now().Add(time.Duration(len(cert)) * time.Millisecond)
+	for path, data := range map[string][]byte{paths.cert: cert, paths.key: key, paths.ca: ca.pem} {
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	return paths
+}
+
+func newReloader(t *testing.T, paths files) *tlsreload.Reloader {
+	reloader, err := tlsreload.New(paths.cert, paths.key, paths.ca)
+	require.NoError(t, err)
+	return reloader
+}
+
+// handshake runs one mTLS handshake between the two reloaders and returns
+// the client's and the server's errors.
+func handshake(t *testing.T, server, client *tlsreload.Reloader, serverName string) (error, error) {
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", server.ServerConfig())
+	require.NoError(t, err)
+	defer listener.Close()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			serverErr <- err
+			return
+		}
+		defer conn.Close()
+		err = conn.(*tls.Conn).Handshake()
+		serverErr <- err
+		if err == nil {
+			// Hold the connection until the client is done with it.
+			io.Copy(io.Discard, conn)
+		}
+	}()
+
+	conn, err := net.DialTimeout("tcp", listener.Addr().String(), time.Second)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	clientConn := tls.Client(conn, client.ClientConfig(serverName))
+	clientErr := clientConn.Handshake()
+	if clientErr == nil {
+		// TLS 1.3 reports a rejected client certificate on the first read.
+		clientConn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+		_, readErr := clientConn.Read(make([]byte, 1))
+		if netErr, ok := readErr.(net.Error); !ok || !netErr.Timeout() {
+			clientErr = readErr
+		}
+	}
+	clientConn.Close()
+
+	return clientErr, <-serverErr
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	server := newReloader(t, write(t, t.TempDir(), ca, "movies"))
+	client := newReloader(t, write(t, t.TempDir(), ca, "apigateway"))
+
+	clientErr, serverErr := handshake(t, server, client, "movies")
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+}
+
+func TestServerRejectsClientFromOtherCA(t *testing.T) {
+	ca, other := newAuthority(t, "ca"), newAuthority(t, "other")
+	server := newReloader(t, write(t, t.TempDir(), ca, "movies"))
+
+	clientFiles := write(t, t.TempDir(), other, "apigateway")
+	// The client trusts the server's CA but presents a foreign certificate.
+	require.NoError(t, os.WriteFile(clientFiles.ca, ca.pem, 0o600))
+	client := newReloader(t, clientFiles)
+
+	clientErr, serverErr := handshake(t, server, client, "movies")
+	assert.Error(t, serverErr)
+	assert.Error(t, clientErr)
+}
+
+func TestClientVerifiesServer(t *testing.T) {
+	ca, other := newAuthority(t, "ca"), newAuthority(t, "other")
+	client := newReloader(t, write(t, t.TempDir(), ca, "apigateway"))
+
+	wrongName := newReloader(t, write(t, t.TempDir(), ca, "movies"))
+	clientErr, _ := handshake(t, wrongName, client, "somewhere-else")
+	assert.ErrorContains(t, clientErr, "somewhere-else")
+
+	serverFiles := write(t, t.TempDir(), other, "movies")
+	require.NoError(t, os.WriteFile(serverFiles.ca, ca.pem, 0o600))
+	wrongCA := newReloader(t, serverFiles)
+	clientErr, _ = handshake(t, wrongCA, client, "movies")
+	assert.ErrorContains(t, clientErr, "unknown authority")
+}
+
+func TestNewRejectsBadFiles(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	paths := write(t, t.TempDir(), ca, "movies")
+
+	_, err := tlsreload.New(paths.cert, filepath.Join(t.TempDir(), "missing.key"), paths.ca)
+	assert.Error(t, err)
+
+	_, err = tlsreload.New(paths.cert, paths.key, filepath.Join(t.TempDir(), "missing.crt"))
+	assert.Error(t, err)
+
+	require.NoError(t, os.WriteFile(paths.ca, []byte("not a certificate"), 0o600))
+	_, err = tlsreload.New(paths.cert, paths.key, paths.ca)
+	assert.ErrorContains(t, err, "no certificates found")
+}
+
+func TestReloadSwapsCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t, "ca")
+	reloader := newReloader(t, write(t, dir, ca, "first"))
+
+	current := func() string {
+		cert, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+	assert.Equal(t, "first", current())
+
+	write(t, dir, ca, "second")
+	assert.Equal(t, "first", current(), "nothing changes until a reload")
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, "second", current())
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("half written"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, "second", current(), "a failed reload keeps the previous certificate")
+}
+
+func TestWatchPicksUpRotatedFiles(t *testing.T) {
+	dir := t.TempDir()
+	ca := newAuthority(t, "ca")
+	reloader := newReloader(t, write(t, dir, ca, "first"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, 10*time.Millisecond, zap.NewNop())
+
+	write(t, dir, ca, "rotated")
+	assert.Eventually(t, func() bool {
+		cert, _ := reloader.GetCertificate(nil)
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		return err == nil && leaf.Subject.CommonName == "rotated"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRotatedCAIsUsedByLiveConfigs(t *testing.T) {
+	serverDir, clientDir := t.TempDir(), t.TempDir()
+	oldCA, newCA := newAuthority(t, "old"), newAuthority(t, "new")
+	server := newReloader(t, write(t, serverDir, oldCA, "movies"))
+	client := newReloader(t, write(t, clientDir, oldCA, "apigateway"))
+
+	// Configs are built once, the way the gRPC server and client hold them.
+	write(t, serverDir, newCA, "movies")
+	require.NoError(t, server.Reload())
+
+	clientErr, _ := handshake(t, server, client, "movies")
+	assert.Error(t, clientErr, "the client still trusts only the old CA")
+
+	write(t, clientDir, newCA, "apigateway")
+	require.NoError(t, client.Reload())
+
+	clientErr, serverErr := handshake(t, server, client, "movies")
+	require.NoError(t, clientErr)
+	require.NoError(t, serverErr)
+}
+
+func TestMutualTLSRefusesWithoutCA(t *testing.T) {
+	ca := newAuthority(t, "ca")
+	serverFiles := write(t, t.TempDir(), ca, "movies")
+	clientFiles := write(t, t.TempDir(), ca, "apigateway")
+
+	withCA := newReloader(t, serverFiles)
+	withoutCA, err := tlsreload.New(clientFiles.cert, clientFiles.key, "")
+	require.NoError(t, err, "a CA-less reloader still serves GetCertificate")
+
+	clientErr, _ := handshake(t, withCA, withoutCA, "movies")
+	assert.ErrorContains(t, clientErr, "CA bundle", "the client must not fall back to the system roots")
+
+	_, serverErr := handshake(t, withoutCA, withCA, "apigateway")
+	assert.ErrorContains(t, serverErr, "CA bundle", "the server must not fall back to the system roots")
+}
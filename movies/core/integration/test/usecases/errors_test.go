@@ -0,0 +1,144 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"movies/core/usecases"
+	"movies/core/util"
+	"proto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// failingRepository answers every call with err.
+type failingRepository struct {
+	err error
+}
+
+func (repo failingRepository) FindAll(context.Context, *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error) {
+	return nil, 0, repo.err
+}
+
+func (repo failingRepository) FindById(context.Context, *proto.MovieIdRequest) (*proto.Movie, error) {
+	return nil, repo.err
+}
+
+func (repo failingRepository) Create(context.Context, *proto.Movie) (*proto.Movie, error) {
+	return nil, repo.err
+}
+
+func (repo failingRepository) Delete(context.Context, uint32) error {
+	return repo.err
+}
+
+func usecase(err error) *usecases.MoviesUsecase {
+	return &usecases.MoviesUsecase{Repository: failingRepository{err: err}, Logger: zap.NewNop()}
+}
+
+func details[T any](st *status.Status) []T {
+	var found []T
+	for _, detail := range st.Details() {
+		if detail, ok := detail.(T); ok {
+			found = append(found, detail)
+		}
+	}
+	return found
+}
+
+func TestRepositoryErrorsMapToStatusDetails(t *testing.T) {
+	cases := map[string]struct {
+		err      error
+		code     codes.Code
+		reason   string
+		resource bool
+		retry    bool
+	}{
+		"not found":   {util.ErrMovieNotFound, codes.NotFound, util.ReasonMovieNotFound, true, false},
+		"conflict":    {fmt.Errorf("%w: dup", util.ErrMovieAlreadyExists), codes.AlreadyExists, util.ReasonMovieConflict, true, false},
+		"timeout":     {fmt.Errorf("%w: slow", util.ErrRepositoryTimeout), codes.DeadlineExceeded, util.ReasonUpstreamTimeout, false, true},
+		"unavailable": {fmt.Errorf("%w: down", util.ErrRepositoryUnavailable), codes.Unavailable, util.ReasonServiceUnavailable, false, true},
+		"other":       {errors.New("boom"), codes.Internal, util.ReasonInternal, false, false},
+	}
+
+	for name, tc := range cases {
+		_, err := usecase(tc.err).GetMovie(context.Background(), &proto.MovieIdRequest{Id: 7})
+		st, ok := status.FromError(err)
+		require.True(t, ok, name)
+		assert.Equal(t, tc.code, st.Code(), name)
+		assert.NotContains(t, st.Message(), "boom", "%s: driver errors stay in the logs", name)
+
+		infos := details[*errdetails.ErrorInfo](st)
+		require.Len(t, infos, 1, name)
+		assert.Equal(t, tc.reason, infos[0].Reason, name)
+		assert.Equal(t, util.ErrorDomain, infos[0].Domain, name)
+
+		resources := details[*errdetails.ResourceInfo](st)
+		if tc.resource {
+			require.Len(t, resources, 1, name)
+			assert.Equal(t, util.ResourceTypeMovie, resources[0].ResourceType, name)
+			assert.Equal(t, "7", resources[0].ResourceName, name)
+		} else {
+			assert.Empty(t, resources, name)
+		}
+
+		retries := details[*errdetails.RetryInfo](st)
+		if tc.retry {
+			require.Len(t, retries, 1, name)
+			assert.Equal(t, 2*time.Second, retries[0].RetryDelay.AsDuration(), name)
+		} else {
+			assert.Empty(t, retries, name)
+		}
+	}
+}
+
+func TestListErrorsHaveNoResourceName(t *testing.T) {
+	_, err := usecase(util.ErrMovieNotFound).GetMovies(context.Background(), &proto.GetMoviesRequest{Page: 1, Limit: 10})
+
+	resources := details[*errdetails.ResourceInfo](status.Convert(err))
+	require.Len(t, resources, 1)
+	assert.Empty(t, resources[0].ResourceName)
+}
+
+func TestCancelledRepositoryCallsStayCancelled(t *testing.T) {
+	_, err := usecase(fmt.Errorf("find: %w", context.Canceled)).DeleteMovie(context.Background(), &proto.MovieIdRequest{Id: 1})
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestBadRequestDetail(t *testing.T) {
+	err := util.StatusError(codes.InvalidArgument, util.ReasonValidationFailed, "invalid movie",
+		util.BadRequest(
+			util.FieldViolation{Field: "title", Description: "must not be empty"},
+			util.FieldViolation{Field: "year", Description: "must be a four digit year"},
+		))
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	badRequests := details[*errdetails.BadRequest](st)
+	require.Len(t, badRequests, 1)
+	require.Len(t, badRequests[0].FieldViolations, 2)
+	assert.Equal(t, "title", badRequests[0].FieldViolations[0].Field)
+	assert.Equal(t, "must be a four digit year", badRequests[0].FieldViolations[1].Description)
+}
+
+func TestGetMoviesRejectsEmptyPaging(t *testing.T) {
+	// The repository is never reached: a zero page would underflow FindAll.
+	_, err := usecase(errors.New("unreachable")).GetMovies(context.Background(), &proto.GetMoviesRequest{})
+
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+
+	badRequests := details[*errdetails.BadRequest](st)
+	require.Len(t, badRequests, 1)
+	require.Len(t, badRequests[0].FieldViolations, 2)
+	assert.Equal(t, "page", badRequests[0].FieldViolations[0].Field)
+	assert.Equal(t, "limit", badRequests[0].FieldViolations[1].Field)
+}
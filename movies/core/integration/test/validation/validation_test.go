@@ -0,0 +1,47 @@
+package validation
+
+import (
+	"movies/core/util"
+	"movies/core/validation"
+	"proto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+func TestValidate(t *testing.T) {
+	cases := map[string]struct {
+		message protobuf.Message
+		want    []util.FieldViolation
+	}{
+		"valid movie":        {&proto.Movie{Title: "The Matrix", Year: "1999"}, nil},
+		"title at the limit": {&proto.Movie{Title: strings.Repeat("é", 200), Year: "1999"}, nil},
+		"empty movie": {&proto.Movie{}, []util.FieldViolation{
+			{Field: "title", Description: "is required"},
+			{Field: "year", Description: "is required"},
+		}},
+		"long title": {&proto.Movie{Title: strings.Repeat("a", 201), Year: "1999"}, []util.FieldViolation{
+			{Field: "title", Description: "must be at most 200 characters"},
+		}},
+		"short year":      {&proto.Movie{Title: "Up", Year: "99"}, []util.FieldViolation{{Field: "year", Description: "must be a four digit year"}}},
+		"non digit year":  {&proto.Movie{Title: "Up", Year: "19a9"}, []util.FieldViolation{{Field: "year", Description: "must be a four digit year"}}},
+		"valid id":        {&proto.MovieIdRequest{Id: 1}, nil},
+		"zero id":         {&proto.MovieIdRequest{}, []util.FieldViolation{{Field: "id", Description: "must be greater than 0"}}},
+		"valid page":      {&proto.GetMoviesRequest{Page: 1, Limit: 100}, nil},
+		"smallest limit":  {&proto.GetMoviesRequest{Page: 3, Limit: 1}, nil},
+		"zero page":       {&proto.GetMoviesRequest{Limit: 10}, []util.FieldViolation{{Field: "page", Description: "must be greater than 0"}}},
+		"zero limit":      {&proto.GetMoviesRequest{Page: 1}, []util.FieldViolation{{Field: "limit", Description: "must be between 1 and 100"}}},
+		"limit too large": {&proto.GetMoviesRequest{Page: 1, Limit: 101}, []util.FieldViolation{{Field: "limit", Description: "must be between 1 and 100"}}},
+		"both paging fields": {&proto.GetMoviesRequest{}, []util.FieldViolation{
+			{Field: "page", Description: "must be greater than 0"},
+			{Field: "limit", Description: "must be between 1 and 100"},
+		}},
+		"message without rules": {&proto.Empty{}, nil},
+	}
+
+	for name, tc := range cases {
+		assert.Equal(t, tc.want, validation.Validate(tc.message), name)
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"pkg/logger"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func AccessLog(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+		}
+
+		requestLog := logger.WithContext(ctx, log)
+
+		switch code {
+		case codes.OK, codes.NotFound, codes.InvalidArgument, codes.AlreadyExists:
+			requestLog.Info("rpc", fields...)
+		default:
+			requestLog.Error("rpc", fields...)
+		}
+
+		return resp, err
+	}
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"context"
+	"pkg/requestid"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// RequestID picks up the X-Request-ID forwarded by the gateway, generating
+// one for direct callers, so log lines on both sides share the same ID.
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(requestid.MetadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+
+		if !requestid.IsValid(id) {
+			id = requestid.Generate()
+		}
+
+		return handler(requestid.NewContext(ctx, id), req)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"movies/core/util"
+	"movies/core/validation"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// Validation rejects requests breaking the rules in core/validation
+// before they reach a usecase, so direct gRPC callers get the same
+// guarantees as traffic coming through the gateway.
+func Validation() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		message, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		if violations := validation.Validate(message); len(violations) > 0 {
+			return nil, util.StatusError(codes.InvalidArgument, util.ReasonValidationFailed,
+				"invalid request", util.BadRequest(violations...))
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -1,235 +0,0 @@
-// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
-// versions:
-// - protoc-gen-go-grpc v1.5.1
-// - protoc             v3.19.6
-// source: proto/movies.proto
-
-package proto
-
-import (
-	context "context"
-	grpc "google.golang.org/grpc"
-	codes "google.golang.org/grpc/codes"
-	status "google.golang.org/grpc/status"
-)
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.64.0 or later.
-const _ = grpc.SupportPackageIsVersion9
-
-const (
-	MovieService_GetMovie_FullMethodName    = "/movies.MovieService/GetMovie"
-	MovieService_GetMovies_FullMethodName   = "/movies.MovieService/GetMovies"
-	MovieService_CreateMovie_FullMethodName = "/movies.MovieService/CreateMovie"
-	MovieService_DeleteMovie_FullMethodName = "/movies.MovieService/DeleteMovie"
-)
-
-// MovieServiceClient is the client API for MovieService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
-type MovieServiceClient interface {
-	GetMovie(ctx context.Context, in *MovieIdRequest, opts ...grpc.CallOption) (*Movie, error)
-	GetMovies(ctx context.Context, in *GetMoviesRequest, opts ...grpc.CallOption) (*MovieListResponse, error)
-	CreateMovie(ctx context.Context, in *Movie, opts ...grpc.CallOption) (*Movie, error)
-	DeleteMovie(ctx context.Context, in *MovieIdRequest, opts ...grpc.CallOption) (*Empty, error)
-}
-
-type movieServiceClient struct {
-	cc grpc.ClientConnInterface
-}
-
-func NewMovieServiceClient(cc grpc.ClientConnInterface) MovieServiceClient {
-	return &movieServiceClient{cc}
-}
-
-func (c *movieServiceClient) GetMovie(ctx context.Context, in *MovieIdRequest, opts ...grpc.CallOption) (*Movie, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Movie)
-	err := c.cc.Invoke(ctx, MovieService_GetMovie_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *movieServiceClient) GetMovies(ctx context.Context, in *GetMoviesRequest, opts ...grpc.CallOption) (*MovieListResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(MovieListResponse)
-	err := c.cc.Invoke(ctx, MovieService_GetMovies_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *movieServiceClient) CreateMovie(ctx context.Context, in *Movie, opts ...grpc.CallOption) (*Movie, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Movie)
-	err := c.cc.Invoke(ctx, MovieService_CreateMovie_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-func (c *movieServiceClient) DeleteMovie(ctx context.Context, in *MovieIdRequest, opts ...grpc.CallOption) (*Empty, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(Empty)
-	err := c.cc.Invoke(ctx, MovieService_DeleteMovie_FullMethodName, in, out, cOpts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
-}
-
-// MovieServiceServer is the server API for MovieService service.
-// All implementations must embed UnimplementedMovieServiceServer
-// for forward compatibility.
-type MovieServiceServer interface {
-	GetMovie(context.Context, *MovieIdRequest) (*Movie, error)
-	GetMovies(context.Context, *GetMoviesRequest) (*MovieListResponse, error)
-	CreateMovie(context.Context, *Movie) (*Movie, error)
-	DeleteMovie(context.Context, *MovieIdRequest) (*Empty, error)
-	mustEmbedUnimplementedMovieServiceServer()
-}
-
-// UnimplementedMovieServiceServer must be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedMovieServiceServer struct{}
-
-func (UnimplementedMovieServiceServer) GetMovie(context.Context, *MovieIdRequest) (*Movie, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMovie not implemented")
-}
-func (UnimplementedMovieServiceServer) GetMovies(context.Context, *GetMoviesRequest) (*MovieListResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetMovies not implemented")
-}
-func (UnimplementedMovieServiceServer) CreateMovie(context.Context, *Movie) (*Movie, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
-}
-func (UnimplementedMovieServiceServer) DeleteMovie(context.Context, *MovieIdRequest) (*Empty, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method DeleteMovie not implemented")
-}
-func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
-func (UnimplementedMovieServiceServer) testEmbeddedByValue()                      {}
-
-// UnsafeMovieServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to MovieServiceServer will
-// result in compilation errors.
-type UnsafeMovieServiceServer interface {
-	mustEmbedUnimplementedMovieServiceServer()
-}
-
-func RegisterMovieServiceServer(s grpc.ServiceRegistrar, srv MovieServiceServer) {
-	// If the following call pancis, it indicates UnimplementedMovieServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
-	}
-	s.RegisterService(&MovieService_ServiceDesc, srv)
-}
-
-func _MovieService_GetMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MovieIdRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MovieServiceServer).GetMovie(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: MovieService_GetMovie_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MovieServiceServer).GetMovie(ctx, req.(*MovieIdRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _MovieService_GetMovies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetMoviesRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MovieServiceServer).GetMovies(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: MovieService_GetMovies_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MovieServiceServer).GetMovies(ctx, req.(*GetMoviesRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _MovieService_CreateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Movie)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MovieServiceServer).CreateMovie(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: MovieService_CreateMovie_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MovieServiceServer).CreateMovie(ctx, req.(*Movie))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-func _MovieService_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(MovieIdRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(MovieServiceServer).DeleteMovie(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: MovieService_DeleteMovie_FullMethodName,
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(MovieServiceServer).DeleteMovie(ctx, req.(*MovieIdRequest))
-	}
-	return interceptor(ctx, in, info, handler)
-}
-
-// MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var MovieService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "movies.MovieService",
-	HandlerType: (*MovieServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "GetMovie",
-			Handler:    _MovieService_GetMovie_Handler,
-		},
-		{
-			MethodName: "GetMovies",
-			Handler:    _MovieService_GetMovies_Handler,
-		},
-		{
-			MethodName: "CreateMovie",
-			Handler:    _MovieService_CreateMovie_Handler,
-		},
-		{
-			MethodName: "DeleteMovie",
-			Handler:    _MovieService_DeleteMovie_Handler,
-		},
-	},
-	Streams:  []grpc.StreamDesc{},
-	Metadata: "proto/movies.proto",
-}
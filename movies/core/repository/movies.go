@@ -1,7 +1,8 @@
 package repository
 
 import (
-	"movies/core/proto"
+	"context"
+	"proto"
 )
 
 type MoviesRepositoryImpl struct {
@@ -9,9 +10,11 @@ type MoviesRepositoryImpl struct {
 	nextID uint32
 }
 
+// MoviesRepository implementations must honour ctx: a cancelled or
+// expired context aborts the operation and its error is returned.
 type MoviesRepository interface {
-	FindAll(req *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error)
-	FindById(req *proto.MovieIdRequest) (*proto.Movie, error)
-	Create(movie *proto.Movie) (*proto.Movie, error)
-	Delete(id uint32) error
+	FindAll(ctx context.Context, req *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error)
+	FindById(ctx context.Context, req *proto.MovieIdRequest) (*proto.Movie, error)
+	Create(ctx context.Context, movie *proto.Movie) (*proto.Movie, error)
+	Delete(ctx context.Context, id uint32) error
 }
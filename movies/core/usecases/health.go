@@ -0,0 +1,107 @@
+package usecases
+
+import (
+	"context"
+	"proto"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const healthWatchInterval = 5 * time.Second
+
+// HealthUsecase implements the standard grpc.health.v1 protocol. Every
+// check pings the database, so a replica that lost Mongo reports
+// NOT_SERVING instead of accepting traffic it cannot handle.
+type HealthUsecase struct {
+	healthpb.UnimplementedHealthServer
+	Ping    func(ctx context.Context) error
+	Timeout time.Duration
+	// WatchInterval is how often Watch re-checks; zero means every 5s.
+	WatchInterval time.Duration
+
+	shuttingDown atomic.Bool
+}
+
+// Shutdown makes every check report NOT_SERVING so clients stop sending
+// new calls while in-flight ones drain.
+func (service *HealthUsecase) Shutdown() {
+	service.shuttingDown.Store(true)
+}
+
+func (service *HealthUsecase) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	if !isKnownService(req.Service) {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.Service)
+	}
+
+	return &healthpb.HealthCheckResponse{Status: service.status(ctx)}, nil
+}
+
+func (service *HealthUsecase) List(ctx context.Context, req *healthpb.HealthListRequest) (*healthpb.HealthListResponse, error) {
+	current := &healthpb.HealthCheckResponse{Status: service.status(ctx)}
+
+	return &healthpb.HealthListResponse{
+		Statuses: map[string]*healthpb.HealthCheckResponse{
+			"": current,
+			proto.MovieService_ServiceDesc.ServiceName: current,
+		},
+	}, nil
+}
+
+func (service *HealthUsecase) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	// The protocol keeps the call open for an unknown service: it may be
+	// registered later, and clients treat a closed stream as a failure.
+	if !isKnownService(req.Service) {
+		if err := stream.Send(&healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN}); err != nil {
+			return err
+		}
+		<-stream.Context().Done()
+		return status.FromContextError(stream.Context().Err()).Err()
+	}
+
+	interval := service.WatchInterval
+	if interval <= 0 {
+		interval = healthWatchInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		current := service.status(stream.Context())
+		if current != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (service *HealthUsecase) status(ctx context.Context) healthpb.HealthCheckResponse_ServingStatus {
+	if service.shuttingDown.Load() {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, service.Timeout)
+	defer cancel()
+
+	if err := service.Ping(pingCtx); err != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	return healthpb.HealthCheckResponse_SERVING
+}
+
+func isKnownService(name string) bool {
+	return name == "" || name == proto.MovieService_ServiceDesc.ServiceName
+}
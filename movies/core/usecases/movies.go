@@ -2,38 +2,56 @@ package usecases
 
 import (
 	"context"
-	"movies/core/proto"
+	"errors"
+	"fmt"
 	"movies/core/repository"
 	"movies/core/util"
+	"pkg/logger"
+	"proto"
+	"strconv"
+	"time"
 
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// retryDelay is the hint sent in RetryInfo when the database is slow or
+// unreachable; the gateway forwards it as Retry-After.
+const retryDelay = 2 * time.Second
+
 type MoviesUsecase struct {
 	proto.UnimplementedMovieServiceServer
 	Repository repository.MoviesRepository
+	Logger     *zap.Logger
 }
 
 func (service *MoviesUsecase) GetMovie(ctx context.Context, req *proto.MovieIdRequest) (*proto.Movie, error) {
-	movie, err := service.Repository.FindById(req)
-
-	if err == util.ErrMovieNotFound {
-		return nil, status.Errorf(codes.NotFound, "movie not found")
-	}
-
+	movie, err := service.Repository.FindById(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to fetch movie")
+		return nil, service.repositoryError(ctx, err, "fetch movie", req.Id)
 	}
 
 	return movie, nil
 }
 
 func (service *MoviesUsecase) GetMovies(ctx context.Context, req *proto.GetMoviesRequest) (*proto.MovieListResponse, error) {
-	movies, total, err := service.Repository.FindAll(req)
+	// The validation interceptor already rejects these, but FindAll
+	// computes Page-1 and must never see a zero page, whoever calls it.
+	var violations []util.FieldViolation
+	if req.Page == 0 {
+		violations = append(violations, util.FieldViolation{Field: "page", Description: "must be greater than zero"})
+	}
+	if req.Limit == 0 {
+		violations = append(violations, util.FieldViolation{Field: "limit", Description: "must be greater than zero"})
+	}
+	if len(violations) > 0 {
+		return nil, util.StatusError(codes.InvalidArgument, util.ReasonValidationFailed, "invalid pagination", util.BadRequest(violations...))
+	}
 
+	movies, total, err := service.Repository.FindAll(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to fetch movies")
+		return nil, service.repositoryError(ctx, err, "fetch movies", 0)
 	}
 
 	return &proto.MovieListResponse{
@@ -45,26 +63,54 @@ func (service *MoviesUsecase) GetMovies(ctx context.Context, req *proto.GetMovie
 }
 
 func (service *MoviesUsecase) CreateMovie(ctx context.Context, req *proto.Movie) (*proto.Movie, error) {
-	movie, err := service.Repository.Create(req)
-
+	movie, err := service.Repository.Create(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to create movie")
+		return nil, service.repositoryError(ctx, err, "create movie", req.Id)
 	}
 
 	return movie, nil
 }
 
 func (service *MoviesUsecase) DeleteMovie(ctx context.Context, req *proto.MovieIdRequest) (*proto.Empty, error) {
-	err := service.Repository.Delete(req.Id)
+	err := service.Repository.Delete(ctx, req.Id)
 	empty := &proto.Empty{}
 
-	if err == util.ErrMovieNotFound {
-		return empty, status.Errorf(codes.NotFound, "movie not found")
-	}
-
 	if err != nil {
-		return empty, status.Errorf(codes.Internal, "failed to delete movie")
+		return empty, service.repositoryError(ctx, err, "delete movie", req.Id)
 	}
 
 	return empty, nil
 }
+
+// repositoryError turns a repository failure into a status with rich
+// details. id is zero for operations that don't target a single movie.
+func (service *MoviesUsecase) repositoryError(ctx context.Context, err error, action string, id uint32) error {
+	name := ""
+	if id != 0 {
+		name = strconv.FormatUint(uint64(id), 10)
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, "request cancelled")
+	case errors.Is(err, util.ErrMovieNotFound):
+		return util.StatusError(codes.NotFound, util.ReasonMovieNotFound, "movie not found",
+			util.ResourceInfo(util.ResourceTypeMovie, name, "movie not found"))
+	case errors.Is(err, util.ErrMovieAlreadyExists):
+		return util.StatusError(codes.AlreadyExists, util.ReasonMovieConflict, "movie already exists",
+			util.ResourceInfo(util.ResourceTypeMovie, name, "movie already exists"))
+	}
+
+	logger.WithContext(ctx, service.Logger).Error(fmt.Sprintf("failed to %s", action), zap.Error(err))
+
+	switch {
+	case errors.Is(err, util.ErrRepositoryTimeout):
+		return util.StatusError(codes.DeadlineExceeded, util.ReasonUpstreamTimeout,
+			fmt.Sprintf("timed out trying to %s", action), util.RetryInfo(retryDelay))
+	case errors.Is(err, util.ErrRepositoryUnavailable):
+		return util.StatusError(codes.Unavailable, util.ReasonServiceUnavailable,
+			"database unavailable", util.RetryInfo(retryDelay))
+	default:
+		return util.StatusError(codes.Internal, util.ReasonInternal, fmt.Sprintf("failed to %s", action))
+	}
+}
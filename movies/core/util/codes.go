@@ -0,0 +1,73 @@
+package util
+
+import (
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// ErrorDomain and the reasons below travel in the ErrorInfo detail of
+// every error status. They mirror the gateway's error catalogue, which
+// turns them into HTTP statuses, so never rename an existing reason.
+const ErrorDomain = "movies.sipub.tech"
+
+const (
+	ReasonValidationFailed   = "VALIDATION_FAILED"
+	ReasonMovieNotFound      = "MOVIE_NOT_FOUND"
+	ReasonMovieConflict      = "MOVIE_CONFLICT"
+	ReasonServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ReasonUpstreamTimeout    = "UPSTREAM_TIMEOUT"
+	ReasonInternal           = "INTERNAL_ERROR"
+)
+
+const ResourceTypeMovie = "movie"
+
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// StatusError builds a status carrying the ErrorInfo for reason plus any
+// extra google.rpc details (BadRequest, ResourceInfo, RetryInfo...).
+func StatusError(code codes.Code, reason, message string, details ...protoadapt.MessageV1) error {
+	st := status.New(code, message)
+
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason: reason,
+		Domain: ErrorDomain,
+	}}, details...)
+
+	withDetails, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+func ResourceInfo(resourceType, name, description string) *errdetails.ResourceInfo {
+	return &errdetails.ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: name,
+		Description:  description,
+	}
+}
+
+func RetryInfo(delay time.Duration) *errdetails.RetryInfo {
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)}
+}
+
+func BadRequest(violations ...FieldViolation) *errdetails.BadRequest {
+	badRequest := &errdetails.BadRequest{}
+	for _, violation := range violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Description,
+		})
+	}
+	return badRequest
+}
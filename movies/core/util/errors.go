@@ -4,3 +4,5 @@ import "errors"
 
 var ErrMovieNotFound = errors.New("movie not found")
 var ErrMovieAlreadyExists = errors.New("movie already exists")
+var ErrRepositoryTimeout = errors.New("repository timed out")
+var ErrRepositoryUnavailable = errors.New("repository unavailable")
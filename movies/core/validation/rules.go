@@ -0,0 +1,102 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Rule checks one field of a request message and returns a description
+// of the problem, or "" when the value is acceptable.
+type Rule struct {
+	Field string
+	Check func(value protoreflect.Value) string
+}
+
+// rules lists the constraints of every request message, keyed by its
+// full proto name. Messages without an entry are accepted as is.
+//
+// They live here rather than as protovalidate options in movies.proto
+// because that file is compiled with plain protoc for both modules:
+// buf.validate options would need the protovalidate .proto files on the
+// protoc include path and the CEL based runtime in the gateway too. The
+// rules still key off the descriptors, so moving them into the .proto
+// later only swaps this table for the annotations.
+var rules = map[protoreflect.FullName][]Rule{
+	"movies.Movie": {
+		{Field: "title", Check: all(required(), maxLength(MaxTitleLength))},
+		{Field: "year", Check: all(required(), pattern(YearPattern, "must be a four digit year"))},
+	},
+	"movies.MovieIdRequest": {
+		{Field: "id", Check: greaterThan(0)},
+	},
+	"movies.GetMoviesRequest": {
+		{Field: "page", Check: greaterThan(0)},
+		{Field: "limit", Check: between(1, 100)},
+	},
+}
+
+// MaxTitleLength and YearPattern are shared with the moviesadmin fixer,
+// which must repair movies into something these rules accept.
+const MaxTitleLength = 200
+
+var YearPattern = regexp.MustCompile(`^[0-9]{4}$`)
+
+func required() func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		if value.String() == "" {
+			return "is required"
+		}
+		return ""
+	}
+}
+
+func maxLength(max int) func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		if utf8.RuneCountInString(value.String()) > max {
+			return fmt.Sprintf("must be at most %d characters", max)
+		}
+		return ""
+	}
+}
+
+func pattern(expression *regexp.Regexp, description string) func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		if !expression.MatchString(value.String()) {
+			return description
+		}
+		return ""
+	}
+}
+
+func greaterThan(min uint64) func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		if value.Uint() <= min {
+			return fmt.Sprintf("must be greater than %d", min)
+		}
+		return ""
+	}
+}
+
+func between(min, max uint64) func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		if number := value.Uint(); number < min || number > max {
+			return fmt.Sprintf("must be between %d and %d", min, max)
+		}
+		return ""
+	}
+}
+
+// all stops at the first failing check so each field reports one problem.
+func all(checks ...func(protoreflect.Value) string) func(protoreflect.Value) string {
+	return func(value protoreflect.Value) string {
+		for _, check := range checks {
+			if description := check(value); description != "" {
+				return description
+			}
+		}
+		return ""
+	}
+}
@@ -0,0 +1,29 @@
+package validation
+
+import (
+	"movies/core/util"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Validate runs the rules registered for the message type and returns
+// every violation found.
+func Validate(message proto.Message) []util.FieldViolation {
+	reflected := message.ProtoReflect()
+	fields := reflected.Descriptor().Fields()
+
+	var violations []util.FieldViolation
+	for _, rule := range rules[reflected.Descriptor().FullName()] {
+		field := fields.ByName(protoreflect.Name(rule.Field))
+		if field == nil {
+			continue
+		}
+
+		if description := rule.Check(reflected.Get(field)); description != "" {
+			violations = append(violations, util.FieldViolation{Field: rule.Field, Description: description})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,59 @@
+// Package bolt stores movies in an embedded bbolt file so the service can
+// run without MongoDB, e.g. for local development and single-node demos.
+package bolt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+var (
+	moviesBucket    = []byte("movies")
+	metaBucket      = []byte("meta")
+	countKey        = []byte("count")
+	buckets         = [][]byte{moviesBucket, metaBucket}
+	openLockTimeout = time.Second
+)
+
+// Open creates or opens the database at path. Seeding is done separately
+// through NewSeedStore.
+func Open(path string) (*bbolt.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: openLockTimeout})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := createBuckets(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func Ping(db *bbolt.DB) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return db.View(func(tx *bbolt.Tx) error {
+			return nil
+		})
+	}
+}
+
+func createBuckets(db *bbolt.DB) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,210 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"movies/core/repository"
+	"movies/core/util"
+	"movies/pkg/metrics"
+	"proto"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+// record is how a movie is stored; the ID doubles as the big-endian key,
+// so iterating the bucket backwards lists the newest movies first.
+type record struct {
+	Id        uint32    `json:"id"`
+	Title     string    `json:"title"`
+	Year      string    `json:"year"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+type MoviesRepositoryImpl struct {
+	db *bbolt.DB
+}
+
+var _ repository.MoviesRepository = (*MoviesRepositoryImpl)(nil)
+
+func NewMoviesRepository(db *bbolt.DB) *MoviesRepositoryImpl {
+	return &MoviesRepositoryImpl{db: db}
+}
+
+func (repo *MoviesRepositoryImpl) FindAll(ctx context.Context, req *proto.GetMoviesRequest) (movies []*proto.Movie, total uint32, err error) {
+	start := time.Now()
+	defer func() { observe("find_all", start, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	movies = []*proto.Movie{}
+	err = repo.db.View(func(tx *bbolt.Tx) error {
+		total = count(tx)
+
+		skip := uint64(req.Page-1) * uint64(req.Limit)
+		cursor := tx.Bucket(moviesBucket).Cursor()
+		for key, value := cursor.Last(); key != nil && uint32(len(movies)) < req.Limit; key, value = cursor.Prev() {
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			movie, err := decode(value)
+			if err != nil {
+				return err
+			}
+			movies = append(movies, movie)
+		}
+
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movies, total, nil
+}
+
+func (repo *MoviesRepositoryImpl) FindById(ctx context.Context, req *proto.MovieIdRequest) (movie *proto.Movie, err error) {
+	start := time.Now()
+	defer func() { observe("find_by_id", start, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	err = repo.db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket(moviesBucket).Get(idKey(req.Id))
+		if value == nil {
+			return util.ErrMovieNotFound
+		}
+
+		movie, err = decode(value)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return movie, nil
+}
+
+func (repo *MoviesRepositoryImpl) Create(ctx context.Context, movie *proto.Movie) (created *proto.Movie, err error) {
+	start := time.Now()
+	defer func() { observe("create", start, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	err = repo.db.Update(func(tx *bbolt.Tx) error {
+		id, err := tx.Bucket(moviesBucket).NextSequence()
+		if err != nil {
+			return err
+		}
+
+		stored := &record{Id: uint32(id), Title: movie.Title, Year: movie.Year, CreatedAt: time.Now().UTC()}
+		if err := put(tx, stored); err != nil {
+			return err
+		}
+
+		created = &proto.Movie{Id: stored.Id, Title: stored.Title, Year: stored.Year}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (repo *MoviesRepositoryImpl) Delete(ctx context.Context, id uint32) (err error) {
+	start := time.Now()
+	defer func() { observe("delete", start, err) }()
+
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	return repo.db.Update(func(tx *bbolt.Tx) error {
+		movies := tx.Bucket(moviesBucket)
+		value := movies.Get(idKey(id))
+		if value == nil {
+			return util.ErrMovieNotFound
+		}
+
+		if err := movies.Delete(idKey(id)); err != nil {
+			return err
+		}
+
+		return setCount(tx, count(tx)-1)
+	})
+}
+
+func idKey(id uint32) []byte {
+	key := make([]byte, 4)
+	binary.BigEndian.PutUint32(key, id)
+	return key
+}
+
+// put writes stored, keeping the count and the ID sequence in step when
+// it is a new movie.
+func put(tx *bbolt.Tx, stored *record) error {
+	movies := tx.Bucket(moviesBucket)
+	isNew := movies.Get(idKey(stored.Id)) == nil
+
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	if err := movies.Put(idKey(stored.Id), value); err != nil {
+		return err
+	}
+
+	if uint64(stored.Id) > movies.Sequence() {
+		if err := movies.SetSequence(uint64(stored.Id)); err != nil {
+			return err
+		}
+	}
+
+	if isNew {
+		return setCount(tx, count(tx)+1)
+	}
+	return nil
+}
+
+func count(tx *bbolt.Tx) uint32 {
+	value := tx.Bucket(metaBucket).Get(countKey)
+	if len(value) != 4 {
+		return 0
+	}
+	return binary.BigEndian.Uint32(value)
+}
+
+func setCount(tx *bbolt.Tx, total uint32) error {
+	return tx.Bucket(metaBucket).Put(countKey, idKey(total))
+}
+
+func decode(value []byte) (*proto.Movie, error) {
+	var stored record
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return nil, err
+	}
+
+	return &proto.Movie{Id: stored.Id, Title: stored.Title, Year: stored.Year}, nil
+}
+
+// observe records the operation's latency. A missing movie is an answer,
+// not a storage failure, as in the Mongo repository.
+func observe(operation string, start time.Time, err error) {
+	if errors.Is(err, util.ErrMovieNotFound) {
+		err = nil
+	}
+	metrics.ObserveBoltOperation(operation, start, err)
+}
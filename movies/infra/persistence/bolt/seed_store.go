@@ -0,0 +1,82 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"movies/seed"
+	"time"
+
+	bbolt "go.etcd.io/bbolt"
+)
+
+type SeedStore struct {
+	db *bbolt.DB
+}
+
+var _ seed.Store = (*SeedStore)(nil)
+
+func NewSeedStore(db *bbolt.DB) *SeedStore {
+	return &SeedStore{db: db}
+}
+
+func (store *SeedStore) Empty(ctx context.Context) (bool, error) {
+	return isEmpty(store.db)
+}
+
+func (store *SeedStore) Lookup(ctx context.Context, ids []uint32) (map[uint32]seed.Movie, error) {
+	existing := make(map[uint32]seed.Movie)
+	err := store.db.View(func(tx *bbolt.Tx) error {
+		movies := tx.Bucket(moviesBucket)
+		for _, id := range ids {
+			value := movies.Get(idKey(id))
+			if value == nil {
+				continue
+			}
+
+			var stored record
+			if err := json.Unmarshal(value, &stored); err != nil {
+				return err
+			}
+			existing[id] = seed.Movie{Id: stored.Id, Title: stored.Title, Year: stored.Year}
+		}
+		return ctx.Err()
+	})
+
+	return existing, err
+}
+
+// Write upserts the batch in one transaction; put moves the bucket
+// sequence past the highest ID so Create continues from there.
+func (store *SeedStore) Write(ctx context.Context, movies []seed.Movie) error {
+	now := time.Now().UTC()
+	return store.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(moviesBucket)
+		for _, movie := range movies {
+			stored := &record{Id: movie.Id, Title: movie.Title, Year: movie.Year, CreatedAt: now}
+
+			if value := bucket.Get(idKey(movie.Id)); value != nil {
+				var previous record
+				if err := json.Unmarshal(value, &previous); err != nil {
+					return err
+				}
+				stored.CreatedAt = previous.CreatedAt
+				stored.UpdatedAt = now
+			}
+
+			if err := put(tx, stored); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	})
+}
+
+func isEmpty(db *bbolt.DB) (bool, error) {
+	empty := true
+	err := db.View(func(tx *bbolt.Tx) error {
+		key, _ := tx.Bucket(moviesBucket).Cursor().First()
+		empty = key == nil
+		return nil
+	})
+	return empty, err
+}
@@ -1,13 +1,16 @@
 package mock
 
 import (
-	"movies/core/proto"
+	"context"
 	"movies/core/repository"
 	"movies/core/util"
+	"proto"
 	"sort"
+	"sync"
 )
 
 type MoviesRepositoryMock struct {
+	mutex  sync.RWMutex
 	movies map[uint32]*proto.Movie
 	nextID uint32
 }
@@ -27,7 +30,14 @@ func NewMoviesRepositoryMock() repository.MoviesRepository {
 	}
 }
 
-func (repo *MoviesRepositoryMock) FindAll(req *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error) {
+func (repo *MoviesRepositoryMock) FindAll(ctx context.Context, req *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
 	movies := make([]*proto.Movie, 0, len(repo.movies))
 	for _, movie := range repo.movies {
 		movies = append(movies, movie)
@@ -50,7 +60,14 @@ func (repo *MoviesRepositoryMock) FindAll(req *proto.GetMoviesRequest) ([]*proto
 	return movies[start:end], uint32(len(movies)), nil
 }
 
-func (repo *MoviesRepositoryMock) FindById(req *proto.MovieIdRequest) (*proto.Movie, error) {
+func (repo *MoviesRepositoryMock) FindById(ctx context.Context, req *proto.MovieIdRequest) (*proto.Movie, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
 	movie, exists := repo.movies[req.Id]
 	if !exists {
 		return nil, util.ErrMovieNotFound
@@ -58,14 +75,28 @@ func (repo *MoviesRepositoryMock) FindById(req *proto.MovieIdRequest) (*proto.Mo
 	return movie, nil
 }
 
-func (repo *MoviesRepositoryMock) Create(movie *proto.Movie) (*proto.Movie, error) {
+func (repo *MoviesRepositoryMock) Create(ctx context.Context, movie *proto.Movie) (*proto.Movie, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	movie.Id = repo.nextID
 	repo.movies[movie.Id] = movie
 	repo.nextID++
 	return movie, nil
 }
 
-func (repo *MoviesRepositoryMock) Delete(id uint32) error {
+func (repo *MoviesRepositoryMock) Delete(ctx context.Context, id uint32) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	if _, exists := repo.movies[id]; !exists {
 		return util.ErrMovieNotFound
 	}
@@ -73,11 +104,17 @@ func (repo *MoviesRepositoryMock) Delete(id uint32) error {
 	return nil
 }
 func (repo *MoviesRepositoryMock) Clear() {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	repo.movies = make(map[uint32]*proto.Movie)
 	repo.nextID = 1
 }
 
 func (repo *MoviesRepositoryMock) Seed(movies []*proto.Movie) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
 	for _, movie := range movies {
 		repo.movies[movie.Id] = movie
 		if movie.Id >= repo.nextID {
@@ -87,5 +124,8 @@ func (repo *MoviesRepositoryMock) Seed(movies []*proto.Movie) {
 }
 
 func (repo *MoviesRepositoryMock) GetNextID() uint32 {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
 	return repo.nextID
 }
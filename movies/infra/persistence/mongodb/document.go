@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"fmt"
+	"proto"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// movieDocument is the stored shape of a movie, as left by the
+// migrations in migrations.go.
+type movieDocument struct {
+	Id        uint32    `bson:"id"`
+	Title     string    `bson:"title"`
+	Year      movieYear `bson:"year"`
+	CreatedAt time.Time `bson:"created_at,omitempty"`
+	UpdatedAt time.Time `bson:"updated_at,omitempty"`
+}
+
+func newMovieDocument(movie *proto.Movie, now time.Time) *movieDocument {
+	return &movieDocument{
+		Id:        movie.Id,
+		Title:     movie.Title,
+		Year:      movieYear(movie.Year),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func (document *movieDocument) proto() *proto.Movie {
+	return &proto.Movie{
+		Id:    document.Id,
+		Title: document.Title,
+		Year:  string(document.Year),
+	}
+}
+
+// movieYear is stored as an int but reads the legacy string form too, so
+// documents written before the year_to_int migration still decode.
+type movieYear string
+
+func (year movieYear) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if number, err := strconv.Atoi(string(year)); err == nil {
+		return bson.MarshalValue(int32(number))
+	}
+	return bson.MarshalValue(string(year))
+}
+
+func (year *movieYear) UnmarshalBSONValue(valueType bsontype.Type, data []byte) error {
+	value := bson.RawValue{Type: valueType, Value: data}
+
+	switch valueType {
+	case bsontype.String:
+		*year = movieYear(value.StringValue())
+	case bsontype.Int32:
+		*year = movieYear(strconv.Itoa(int(value.Int32())))
+	case bsontype.Int64:
+		*year = movieYear(strconv.FormatInt(value.Int64(), 10))
+	case bsontype.Double:
+		*year = movieYear(strconv.Itoa(int(value.Double())))
+	case bsontype.Null:
+		*year = ""
+	default:
+		return fmt.Errorf("cannot decode %s into a movie year", valueType)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"movies/core/util"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+// TranslateError maps driver errors onto the repository errors the
+// usecases understand, keeping the original error in the chain for logs.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	switch {
+	case errors.Is(err, mongo.ErrNoDocuments):
+		return util.ErrMovieNotFound
+	case mongo.IsDuplicateKeyError(err):
+		return fmt.Errorf("%w: %w", util.ErrMovieAlreadyExists, err)
+	case errors.As(err, &topology.ServerSelectionError{}), mongo.IsNetworkError(err):
+		return fmt.Errorf("%w: %w", util.ErrRepositoryUnavailable, err)
+	case errors.Is(err, context.DeadlineExceeded), mongo.IsTimeout(err):
+		return fmt.Errorf("%w: %w", util.ErrRepositoryTimeout, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,211 @@
+package mongodb
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"movies/core/util"
+	"proto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Maintenance helpers used by cmd/moviesadmin. They take the collection
+// explicitly so they work without ConnectToMongo's package state.
+
+const restoreBatchSize = 1000
+
+// maxDocumentSize is the BSON document limit; a restore header claiming
+// more is a corrupt archive, not something to allocate for.
+const maxDocumentSize = 16 << 20
+
+// RebuildIndexes creates the expected indexes, then drops every other one
+// except _id, returning the names of the indexes it dropped. Creating
+// first means the collection is never left without the unique id index;
+// an expected index that exists with other options makes it fail instead
+// of being replaced.
+func RebuildIndexes(ctx context.Context, collection *mongo.Collection) ([]string, error) {
+	if err := CreateIndexes(ctx, collection); err != nil {
+		return nil, err
+	}
+
+	expected := map[string]bool{"_id_": true}
+	for _, model := range indexModels {
+		expected[*model.Options.Name] = true
+	}
+
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing []struct {
+		Name string `bson:"name"`
+	}
+	if err := cursor.All(ctx, &existing); err != nil {
+		return nil, err
+	}
+
+	var dropped []string
+	for _, index := range existing {
+		if expected[index.Name] {
+			continue
+		}
+		if _, err := collection.Indexes().DropOne(ctx, index.Name); err != nil {
+			return dropped, err
+		}
+		dropped = append(dropped, index.Name)
+	}
+
+	return dropped, nil
+}
+
+// ForEachMovie decodes every document in id order and calls fn with it.
+func ForEachMovie(ctx context.Context, collection *mongo.Collection, fn func(movie *proto.Movie) error) error {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "id", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var document movieDocument
+		if err := cursor.Decode(&document); err != nil {
+			return fmt.Errorf("document %v: %w", cursor.Current.Lookup("_id"), err)
+		}
+		if err := fn(document.proto()); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// UpdateMovie rewrites the title and year of the movie with movie.Id.
+func UpdateMovie(ctx context.Context, collection *mongo.Collection, movie *proto.Movie) error {
+	result, err := collection.UpdateOne(ctx,
+		bson.M{"id": movie.Id},
+		bson.M{"$set": bson.M{"title": movie.Title, "year": movieYear(movie.Year), "updated_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return TranslateError(err)
+	}
+	if result.MatchedCount == 0 {
+		return util.ErrMovieNotFound
+	}
+	return nil
+}
+
+// Dump writes every document of collection to w as concatenated BSON, the
+// format mongodump uses for a collection's .bson file, so the archive can
+// also be read with bsondump or mongorestore.
+func Dump(ctx context.Context, collection *mongo.Collection, w io.Writer) (int, error) {
+	cursor, err := collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	count := 0
+	for cursor.Next(ctx) {
+		if _, err := w.Write(cursor.Current); err != nil {
+			return count, err
+		}
+		count++
+	}
+
+	return count, cursor.Err()
+}
+
+// Restore inserts the documents of a Dump archive into collection as they
+// are, _id included, in unordered batches.
+func Restore(ctx context.Context, collection *mongo.Collection, r io.Reader) (int, error) {
+	reader := bufio.NewReader(r)
+	batch := make([]interface{}, 0, restoreBatchSize)
+	count := 0
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if _, err := collection.InsertMany(ctx, batch, options.InsertMany().SetOrdered(false)); err != nil {
+			return TranslateError(err)
+		}
+		count += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		document, err := readDocument(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return count, fmt.Errorf("archive document %d: %w", count+len(batch)+1, err)
+		}
+
+		batch = append(batch, document)
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return count, err
+			}
+		}
+	}
+
+	return count, flush()
+}
+
+// RestoreReplacing restores a Dump archive into a temporary collection
+// and only renames it over collection once every document and index is
+// in place, so a bad archive leaves the current data untouched.
+func RestoreReplacing(ctx context.Context, collection *mongo.Collection, r io.Reader) (int, error) {
+	db := collection.Database()
+	staging := db.Collection(fmt.Sprintf("%s_restore_%d", collection.Name(), time.Now().UnixNano()))
+
+	count, err := Restore(ctx, staging, r)
+	if err == nil {
+		err = CreateIndexes(ctx, staging)
+	}
+	if err == nil {
+		err = db.Client().Database("admin").RunCommand(ctx, bson.D{
+			{Key: "renameCollection", Value: db.Name() + "." + staging.Name()},
+			{Key: "to", Value: db.Name() + "." + collection.Name()},
+			{Key: "dropTarget", Value: true},
+		}).Err()
+	}
+	if err != nil {
+		// Best effort: the staging collection holds nothing worth keeping.
+		staging.Drop(context.WithoutCancel(ctx))
+		return count, err
+	}
+
+	return count, nil
+}
+
+// readDocument reads one BSON document, which starts with its own length.
+func readDocument(reader *bufio.Reader) (bson.Raw, error) {
+	header, err := reader.Peek(4)
+	if err == io.EOF && len(header) == 0 {
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	size := int(binary.LittleEndian.Uint32(header))
+	if size < 5 || size > maxDocumentSize {
+		return nil, fmt.Errorf("invalid document length %d", size)
+	}
+
+	document := make(bson.Raw, size)
+	if _, err := io.ReadFull(reader, document); err != nil {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return document, document.Validate()
+}
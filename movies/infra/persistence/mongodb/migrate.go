@@ -0,0 +1,156 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.uber.org/zap"
+)
+
+const (
+	migrationsCollection = "_migrations"
+	migrationLockID      = "lock"
+)
+
+// Lock timings: a lease outlives any sane migration but still expires if
+// the replica holding it dies, and waiters poll until they get it.
+var (
+	migrationLockLease   = 5 * time.Minute
+	migrationLockPoll    = time.Second
+	migrationLockTimeout = 2 * time.Minute
+)
+
+var ErrMigrationLocked = errors.New("another instance is running migrations")
+
+// Migration changes the stored data of the movies collection. Up must be
+// idempotent: it may run again if a replica dies before recording it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(ctx context.Context, collection *mongo.Collection) error
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt time.Time
+}
+
+type migrationRecord struct {
+	Version   int       `bson:"_id"`
+	Name      string    `bson:"name"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// Migrate applies the pending migrations in version order while holding
+// the migration lock, recording each one in _migrations once it succeeds.
+func Migrate(ctx context.Context, db *mongo.Database, collectionName string, log *zap.Logger) error {
+	release, err := acquireMigrationLock(ctx, db)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	statuses, err := MigrationStatuses(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	collection := db.Collection(collectionName)
+	for _, status := range statuses {
+		if !status.AppliedAt.IsZero() {
+			continue
+		}
+
+		if err := status.Up(ctx, collection); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", status.Version, status.Name, err)
+		}
+
+		record := migrationRecord{Version: status.Version, Name: status.Name, AppliedAt: time.Now().UTC()}
+		if _, err := db.Collection(migrationsCollection).InsertOne(ctx, record); err != nil {
+			return err
+		}
+
+		log.Info("Applied migration", zap.Int("version", status.Version), zap.String("name", status.Name))
+	}
+
+	return nil
+}
+
+// MigrationStatuses lists every known migration, with AppliedAt set for
+// the ones already recorded.
+func MigrationStatuses(ctx context.Context, db *mongo.Database) ([]MigrationStatus, error) {
+	cursor, err := db.Collection(migrationsCollection).Find(ctx, bson.M{"_id": bson.M{"$type": "number"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var records []migrationRecord
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]time.Time, len(records))
+	for _, record := range records {
+		applied[record.Version] = record.AppliedAt
+	}
+
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	statuses := make([]MigrationStatus, 0, len(sorted))
+	for _, migration := range sorted {
+		statuses = append(statuses, MigrationStatus{Migration: migration, AppliedAt: applied[migration.Version]})
+	}
+
+	return statuses, nil
+}
+
+// acquireMigrationLock takes the lease document in _migrations, waiting
+// for the current holder while its lease is valid.
+func acquireMigrationLock(ctx context.Context, db *mongo.Database) (func(), error) {
+	collection := db.Collection(migrationsCollection)
+	owner := lockOwner()
+
+	ctx, cancel := context.WithTimeout(ctx, migrationLockTimeout)
+	defer cancel()
+
+	for {
+		now := time.Now().UTC()
+		filter := bson.M{"_id": migrationLockID, "expires_at": bson.M{"$lt": now}}
+		update := bson.M{"$set": bson.M{"owner": owner, "expires_at": now.Add(migrationLockLease)}}
+
+		_, err := collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+		if err == nil {
+			return func() {
+				releaseCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				collection.DeleteOne(releaseCtx, bson.M{"_id": migrationLockID, "owner": owner})
+			}, nil
+		}
+
+		// A live lease makes the upsert collide with the existing _id.
+		if !mongo.IsDuplicateKeyError(err) {
+			return nil, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ErrMigrationLocked
+		case <-time.After(migrationLockPoll):
+		}
+	}
+}
+
+func lockOwner() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d-%d", host, os.Getpid(), time.Now().UnixNano())
+}
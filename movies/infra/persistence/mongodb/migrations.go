@@ -0,0 +1,53 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// migrations is the ordered history of the movies collection schema.
+// Append new ones; never edit or renumber a released migration.
+var migrations = []Migration{
+	{Version: 1, Name: "backfill_timestamps", Up: backfillTimestamps},
+	{Version: 2, Name: "year_to_int", Up: convertYearToInt},
+}
+
+// backfillTimestamps renames the legacy createdAt field and sets
+// created_at/updated_at on documents that never had them.
+func backfillTimestamps(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"createdAt": bson.M{"$exists": true}, "created_at": bson.M{"$exists": false}},
+		bson.M{"$rename": bson.M{"createdAt": "created_at"}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateMany(ctx,
+		bson.M{"created_at": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"created_at": "$$NOW"}}}},
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateMany(ctx,
+		bson.M{"updated_at": bson.M{"$exists": false}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"updated_at": "$created_at"}}}},
+	)
+	return err
+}
+
+// convertYearToInt turns numeric string years into ints. Anything that
+// doesn't parse is left untouched rather than failing the migration.
+func convertYearToInt(ctx context.Context, collection *mongo.Collection) error {
+	_, err := collection.UpdateMany(ctx,
+		bson.M{"year": bson.M{"$type": "string"}},
+		mongo.Pipeline{{{Key: "$set", Value: bson.M{"year": bson.M{
+			"$convert": bson.M{"input": bson.M{"$trim": bson.M{"input": "$year"}}, "to": "int", "onError": "$year"},
+		}}}}},
+	)
+	return err
+}
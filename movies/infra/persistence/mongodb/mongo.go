@@ -2,36 +2,43 @@ package mongodb
 
 import (
 	"context"
-	"encoding/json"
 	"movies/core/config"
-	"os"
-	"path/filepath"
-	"runtime"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 	"go.uber.org/zap"
 )
 
 var collection *mongo.Collection
 var log *zap.Logger
-var currentID int = 0
+var currentID atomic.Uint32
 
 func SetLogger(logger *zap.Logger) {
 	log = logger
 }
 
-func ConnectToMongo(cfg *config.Config) (*mongo.Client, error) {
-	clientOptions := options.Client().ApplyURI(cfg.DBUri)
+// Connect opens a client for cfg without touching the collection; the
+// migrate command uses it directly, the service goes through ConnectToMongo.
+func Connect(cfg *config.Config) (*mongo.Client, error) {
+	clientOptions := options.Client().ApplyURI(cfg.DBUri).SetMonitor(otelmongo.NewMonitor())
+
+	// Credentials may also come inside MONGO_DB_URI.
+	if cfg.DBUser != "" {
+		clientOptions.SetAuth(options.Credential{
+			Username: cfg.DBUser,
+			Password: cfg.DBPassword,
+		})
+	}
 
-	clientOptions.SetAuth(options.Credential{
-		Username: cfg.DBUser,
-		Password: cfg.DBPassword,
-	})
+	return mongo.Connect(context.Background(), clientOptions)
+}
 
-	client, err := mongo.Connect(context.Background(), clientOptions)
+func ConnectToMongo(cfg *config.Config) (*mongo.Client, error) {
+	client, err := Connect(cfg)
 	if err != nil {
 		log.Fatal(err.Error())
 		return nil, err
@@ -45,14 +52,9 @@ func ConnectToMongo(cfg *config.Config) (*mongo.Client, error) {
 		return nil, uniqueErr
 	}
 
-	count, err := collection.CountDocuments(context.Background(), bson.M{})
-	if err != nil {
-		log.Fatal(err.Error())
-	}
-
-	if count == 0 {
-		log.Info("Seeding from JSON file...")
-		if err := SeedFromJSON(context.Background(), collection); err != nil {
+	if cfg.MongoMigrateOnStart {
+		if err := Migrate(context.Background(), client.Database(cfg.DbName), cfg.DbCollection, log); err != nil {
+			log.Error("Failed to run migrations", zap.Error(err))
 			return nil, err
 		}
 	}
@@ -74,13 +76,18 @@ func createUniqueIndexes() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	indexModels := []mongo.IndexModel{
-		{
-			Keys:    bson.D{{Key: "id", Value: 1}},
-			Options: options.Index().SetUnique(true).SetName("unique_id"),
-		},
-	}
+	return CreateIndexes(ctx, collection)
+}
 
+// indexModels are the indexes the movies collection is expected to have.
+var indexModels = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "id", Value: 1}},
+		Options: options.Index().SetUnique(true).SetName("unique_id"),
+	},
+}
+
+func CreateIndexes(ctx context.Context, collection *mongo.Collection) error {
 	_, err := collection.Indexes().CreateMany(ctx, indexModels)
 	return err
 }
@@ -89,6 +96,25 @@ func initializeCurrentID() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	return ResetCurrentID(ctx, collection)
+}
+
+// ResetCurrentID numbers the next movies after the highest id in
+// collection. The counter is shared by every repository in the process,
+// so tests opening a fresh collection call it before creating movies.
+func ResetCurrentID(ctx context.Context, collection *mongo.Collection) error {
+	maxID, err := MaxID(ctx, collection)
+	if err != nil {
+		return err
+	}
+
+	currentID.Store(maxID)
+	return nil
+}
+
+// MaxID is the highest movie id in collection, or 0 when it's empty. New
+// movies are numbered from there.
+func MaxID(ctx context.Context, collection *mongo.Collection) (uint32, error) {
 	pipeline := mongo.Pipeline{
 		{{Key: "$group", Value: bson.D{
 			{Key: "_id", Value: nil},
@@ -98,51 +124,23 @@ func initializeCurrentID() error {
 
 	cursor, err := collection.Aggregate(ctx, pipeline)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer cursor.Close(ctx)
 
-	if cursor.Next(ctx) {
-		var result struct {
-			MaxID int `bson:"maxId"`
-		}
-		if err := cursor.Decode(&result); err != nil {
-			return err
-		}
-		currentID = result.MaxID
-	} else {
-		currentID = 0
-	}
-
-	return nil
-}
-
-func GetNextID() uint32 {
-	currentID++
-	return uint32(currentID)
-}
-
-func SeedFromJSON(ctx context.Context, collection *mongo.Collection) error {
-	data, err := os.ReadFile(GetSeedPath())
-	if err != nil {
-		return err
+	if !cursor.Next(ctx) {
+		return 0, cursor.Err()
 	}
 
-	var movies []interface{}
-	if err := json.Unmarshal(data, &movies); err != nil {
-		return err
+	var result struct {
+		MaxID int `bson:"maxId"`
 	}
-
-	if len(movies) > 0 {
-		_, err = collection.InsertMany(ctx, movies)
-		return err
+	if err := cursor.Decode(&result); err != nil {
+		return 0, err
 	}
-
-	return nil
+	return uint32(result.MaxID), nil
 }
 
-func GetSeedPath() string {
-	_, filename, _, _ := runtime.Caller(0)
-	dir := filepath.Dir(filename)
-	return filepath.Join(dir, "..", "..", "..", "seed", "movies.json")
+func GetNextID() uint32 {
+	return currentID.Add(1)
 }
@@ -2,9 +2,12 @@ package mongodb
 
 import (
 	"context"
-	"movies/core/proto"
+	"errors"
 	"movies/core/repository"
 	"movies/core/util"
+	"movies/pkg/metrics"
+	"proto"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -12,76 +15,104 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// Upper bounds for a single operation. The caller's context still wins
+// when its deadline is shorter or it gets cancelled.
+const (
+	readTimeout  = 5 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
 type MoviesRepositoryImpl struct {
-	collection *mongo.Collection
+	// collection is swapped by the Rotator when credentials change.
+	collection atomic.Pointer[mongo.Collection]
 }
 
 func NewMoviesRepository(client *mongo.Client, dbName, collectionName string) repository.MoviesRepository {
-	collection := client.Database(dbName).Collection(collectionName)
-	return &MoviesRepositoryImpl{collection: collection}
+	repo := &MoviesRepositoryImpl{}
+	repo.collection.Store(client.Database(dbName).Collection(collectionName))
+	return repo
 }
 
-func (repo *MoviesRepositoryImpl) FindAll(req *proto.GetMoviesRequest) ([]*proto.Movie, uint32, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (repo *MoviesRepositoryImpl) FindAll(ctx context.Context, req *proto.GetMoviesRequest) (movies []*proto.Movie, total uint32, err error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
+	start := time.Now()
+	defer func() { metrics.ObserveMongoOperation("find_all", start, err) }()
+
 	opts := options.Find().
 		SetSkip(int64(req.Page-1) * int64(req.Limit)).
 		SetLimit(int64(req.Limit)).
-		SetSort(bson.M{"createdAt": -1})
+		SetSort(bson.D{{Key: "id", Value: -1}})
 
-	cursor, err := repo.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := repo.collection.Load().Find(ctx, bson.M{}, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, TranslateError(err)
 	}
 	defer cursor.Close(ctx)
 
-	var movies []*proto.Movie
-	if err = cursor.All(ctx, &movies); err != nil {
-		return nil, 0, err
+	var documents []movieDocument
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, 0, TranslateError(err)
+	}
+
+	movies = make([]*proto.Movie, 0, len(documents))
+	for i := range documents {
+		movies = append(movies, documents[i].proto())
 	}
 
-	total, err := repo.collection.CountDocuments(ctx, bson.M{})
+	count, err := repo.collection.Load().CountDocuments(ctx, bson.M{})
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, TranslateError(err)
 	}
 
-	return movies, uint32(total), nil
+	return movies, uint32(count), nil
 }
 
-func (repo *MoviesRepositoryImpl) FindById(req *proto.MovieIdRequest) (*proto.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (repo *MoviesRepositoryImpl) FindById(ctx context.Context, req *proto.MovieIdRequest) (*proto.Movie, error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
 	defer cancel()
 
-	var movie proto.Movie
-	repo.collection.FindOne(ctx, bson.M{"id": req.Id}).Decode(&movie)
-	if movie.Id != req.Id {
+	start := time.Now()
+	var document movieDocument
+	err := repo.collection.Load().FindOne(ctx, bson.M{"id": req.Id}).Decode(&document)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		metrics.ObserveMongoOperation("find_by_id", start, nil)
 		return nil, util.ErrMovieNotFound
 	}
 
-	return &movie, nil
+	metrics.ObserveMongoOperation("find_by_id", start, err)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+
+	return document.proto(), nil
 }
 
-func (repo *MoviesRepositoryImpl) Create(movie *proto.Movie) (*proto.Movie, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (repo *MoviesRepositoryImpl) Create(ctx context.Context, movie *proto.Movie) (*proto.Movie, error) {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
 	defer cancel()
 
+	start := time.Now()
 	movie.Id = GetNextID()
-	_, err := repo.collection.InsertOne(ctx, movie)
+	_, err := repo.collection.Load().InsertOne(ctx, newMovieDocument(movie, start.UTC()))
+	metrics.ObserveMongoOperation("create", start, err)
 	if err != nil {
-		return nil, err
+		return nil, TranslateError(err)
 	}
 
 	return movie, nil
 }
 
-func (repo *MoviesRepositoryImpl) Delete(id uint32) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+func (repo *MoviesRepositoryImpl) Delete(ctx context.Context, id uint32) error {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
 	defer cancel()
 
-	result, err := repo.collection.DeleteOne(ctx, bson.M{"id": id})
+	start := time.Now()
+	result, err := repo.collection.Load().DeleteOne(ctx, bson.M{"id": id})
+	metrics.ObserveMongoOperation("delete", start, err)
 	if err != nil {
-		return err
+		return TranslateError(err)
 	}
 
 	if result.DeletedCount == 0 {
@@ -0,0 +1,118 @@
+package mongodb
+
+import (
+	"context"
+	"movies/core/config"
+	"movies/core/repository"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.uber.org/zap"
+)
+
+// drainTimeout is how long a replaced client is kept open so operations
+// already running on it can finish.
+const drainTimeout = writeTimeout
+
+// Rotator keeps the repository on a client authenticated with the current
+// Mongo credentials. Watch polls the secret providers and, when the user,
+// password or URI changed, connects with the new ones, checks them with a
+// ping and swaps the repository over, so a rotated password doesn't need
+// a restart.
+type Rotator struct {
+	mutex  sync.Mutex
+	cfg    config.Config
+	client *mongo.Client
+	repo   *MoviesRepositoryImpl
+}
+
+func NewRotator(cfg config.Config, client *mongo.Client) *Rotator {
+	repo := NewMoviesRepository(client, cfg.DbName, cfg.DbCollection).(*MoviesRepositoryImpl)
+	return &Rotator{cfg: cfg, client: client, repo: repo}
+}
+
+func (rotator *Rotator) Repository() repository.MoviesRepository {
+	return rotator.repo
+}
+
+func (rotator *Rotator) current() *mongo.Client {
+	rotator.mutex.Lock()
+	defer rotator.mutex.Unlock()
+	return rotator.client
+}
+
+func (rotator *Rotator) Ping(ctx context.Context) error {
+	return rotator.current().Ping(ctx, readpref.Primary())
+}
+
+func (rotator *Rotator) Disconnect(ctx context.Context) error {
+	return rotator.current().Disconnect(ctx)
+}
+
+func (rotator *Rotator) Watch(ctx context.Context, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		rotated, err := rotator.Rotate(ctx)
+		if err != nil {
+			log.Error("Failed to rotate Mongo credentials, keeping the current connection", zap.Error(err))
+			continue
+		}
+		if rotated {
+			log.Info("Mongo credentials rotated, reconnected")
+		}
+	}
+}
+
+// Rotate reloads the secrets once and reconnects if the Mongo ones
+// changed; other secrets such as ADMIN_TOKEN or POSTGRES_DSN don't touch
+// the connection. The previous client stays in use when the new
+// credentials don't work.
+func (rotator *Rotator) Rotate(ctx context.Context) (bool, error) {
+	rotator.mutex.Lock()
+	cfg := rotator.cfg
+	rotator.mutex.Unlock()
+
+	next, changed, err := cfg.ReloadSecrets(ctx)
+	if err != nil || !changed || !credentialsChanged(cfg, next) {
+		return false, err
+	}
+
+	client, err := Connect(&next)
+	if err != nil {
+		return false, err
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+	if err := client.Ping(pingCtx, readpref.Primary()); err != nil {
+		client.Disconnect(context.Background())
+		return false, err
+	}
+
+	rotator.mutex.Lock()
+	previous := rotator.client
+	rotator.cfg, rotator.client = next, client
+	rotator.repo.collection.Store(client.Database(next.DbName).Collection(next.DbCollection))
+	rotator.mutex.Unlock()
+
+	time.AfterFunc(drainTimeout, func() {
+		previous.Disconnect(context.Background())
+	})
+	return true, nil
+}
+
+func credentialsChanged(previous, next config.Config) bool {
+	return previous.DBUri != next.DBUri ||
+		previous.DBUser != next.DBUser ||
+		previous.DBPassword != next.DBPassword
+}
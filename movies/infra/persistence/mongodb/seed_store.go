@@ -0,0 +1,73 @@
+package mongodb
+
+import (
+	"context"
+	"movies/seed"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type SeedStore struct {
+	collection *mongo.Collection
+}
+
+var _ seed.Store = (*SeedStore)(nil)
+
+func NewSeedStore(collection *mongo.Collection) *SeedStore {
+	return &SeedStore{collection: collection}
+}
+
+func (store *SeedStore) Empty(ctx context.Context) (bool, error) {
+	count, err := store.collection.CountDocuments(ctx, bson.M{}, options.Count().SetLimit(1))
+	return count == 0, err
+}
+
+func (store *SeedStore) Lookup(ctx context.Context, ids []uint32) (map[uint32]seed.Movie, error) {
+	cursor, err := store.collection.Find(ctx, bson.M{"id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []movieDocument
+	if err := cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	existing := make(map[uint32]seed.Movie, len(documents))
+	for _, document := range documents {
+		existing[document.Id] = seed.Movie{Id: document.Id, Title: document.Title, Year: string(document.Year)}
+	}
+	return existing, nil
+}
+
+// Write upserts the batch by id, keeping created_at of movies that were
+// already there, and moves the ID counter past the highest seeded ID.
+func (store *SeedStore) Write(ctx context.Context, movies []seed.Movie) error {
+	now := time.Now().UTC()
+	models := make([]mongo.WriteModel, 0, len(movies))
+	var maxID uint32
+	for _, movie := range movies {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"id": movie.Id}).
+			SetUpdate(bson.M{
+				"$set":         bson.M{"title": movie.Title, "year": movieYear(movie.Year), "updated_at": now},
+				"$setOnInsert": bson.M{"created_at": now},
+			}).
+			SetUpsert(true))
+		maxID = max(maxID, movie.Id)
+	}
+
+	if _, err := store.collection.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return TranslateError(err)
+	}
+
+	for {
+		current := currentID.Load()
+		if current >= maxID || currentID.CompareAndSwap(current, maxID) {
+			return nil
+		}
+	}
+}
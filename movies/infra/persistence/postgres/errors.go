@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"movies/core/util"
+	"net"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+// TranslateError maps driver errors onto the repository errors the
+// usecases understand, keeping the original error in the chain for logs.
+func TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var pgErr *pgconn.PgError
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return util.ErrMovieNotFound
+	case errors.As(err, &pgErr) && pgErr.Code == uniqueViolation:
+		return fmt.Errorf("%w: %w", util.ErrMovieAlreadyExists, err)
+	case errors.Is(err, context.DeadlineExceeded), pgconn.Timeout(err):
+		return fmt.Errorf("%w: %w", util.ErrRepositoryTimeout, err)
+	case errors.Is(err, sql.ErrConnDone), errors.As(err, &netErr):
+		return fmt.Errorf("%w: %w", util.ErrRepositoryUnavailable, err)
+	default:
+		return err
+	}
+}
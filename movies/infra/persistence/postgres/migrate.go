@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockID is the advisory lock held while migrating, so replicas
+// starting together don't apply the same migration twice.
+const migrationLockID = 7087851
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Migrate applies every embedded migration newer than the recorded schema
+// version, each one in its own transaction. Files are named
+// <version>_<description>.sql and must never change once released.
+func Migrate(ctx context.Context, db *sql.DB, log *zap.Logger) error {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockID); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockID)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    integer PRIMARY KEY,
+		name       text        NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT now()
+	)`); err != nil {
+		return err
+	}
+
+	var current int
+	if err := conn.QueryRowContext(ctx, "SELECT COALESCE(MAX(version), 0) FROM schema_migrations").Scan(&current); err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		if migration.version <= current {
+			continue
+		}
+
+		if err := apply(ctx, conn, migration); err != nil {
+			return fmt.Errorf("migration %d (%s): %w", migration.version, migration.name, err)
+		}
+		log.Info("Applied migration", zap.Int("version", migration.version), zap.String("name", migration.name))
+	}
+
+	return nil
+}
+
+func apply(ctx context.Context, conn *sql.Conn, migration migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.sql); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
+		migration.version, migration.name,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, entry := range entries {
+		prefix, name, ok := strings.Cut(strings.TrimSuffix(entry.Name(), ".sql"), "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %q is not named <version>_<description>.sql", entry.Name())
+		}
+
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("migration %q has an invalid version: %w", entry.Name(), err)
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{version: version, name: name, sql: string(content)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
@@ -0,0 +1,13 @@
+CREATE SEQUENCE IF NOT EXISTS movies_id_seq AS integer;
+
+CREATE TABLE IF NOT EXISTS movies (
+    id         integer PRIMARY KEY DEFAULT nextval('movies_id_seq'),
+    title      text        NOT NULL,
+    year       text        NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+ALTER SEQUENCE movies_id_seq OWNED BY movies.id;
+
+CREATE INDEX IF NOT EXISTS movies_year_idx ON movies (year);
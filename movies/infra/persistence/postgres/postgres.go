@@ -0,0 +1,43 @@
+// Package postgres stores movies in PostgreSQL through database/sql and
+// the pgx driver. The schema is managed by the migrations embedded in the
+// binary and applied on startup.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+const connectTimeout = 10 * time.Second
+
+// Open connects to dsn and migrates the schema. Seeding is done separately
+// through NewSeedStore.
+func Open(dsn string, log *zap.Logger) (*sql.DB, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	if err := Migrate(ctx, db, log); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func Ping(db *sql.DB) func(ctx context.Context) error {
+	return db.PingContext
+}
@@ -0,0 +1,143 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"movies/core/repository"
+	"movies/core/util"
+	"movies/pkg/metrics"
+	"proto"
+	"time"
+)
+
+// Upper bounds for a single operation. The caller's context still wins
+// when its deadline is shorter or it gets cancelled.
+const (
+	readTimeout  = 5 * time.Second
+	writeTimeout = 10 * time.Second
+)
+
+type MoviesRepositoryImpl struct {
+	db *sql.DB
+}
+
+var _ repository.MoviesRepository = (*MoviesRepositoryImpl)(nil)
+
+func NewMoviesRepository(db *sql.DB) *MoviesRepositoryImpl {
+	return &MoviesRepositoryImpl{db: db}
+}
+
+func (repo *MoviesRepositoryImpl) FindAll(ctx context.Context, req *proto.GetMoviesRequest) (movies []*proto.Movie, total uint32, err error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { observe("find_all", start, err) }()
+
+	if err = repo.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM movies").Scan(&total); err != nil {
+		return nil, 0, TranslateError(err)
+	}
+
+	movies, err = repo.query(ctx,
+		"SELECT id, title, year FROM movies ORDER BY id DESC LIMIT $1 OFFSET $2",
+		int64(req.Limit), int64(req.Page-1)*int64(req.Limit),
+	)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movies, total, nil
+}
+
+func (repo *MoviesRepositoryImpl) FindById(ctx context.Context, req *proto.MovieIdRequest) (movie *proto.Movie, err error) {
+	ctx, cancel := context.WithTimeout(ctx, readTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { observe("find_by_id", start, err) }()
+
+	movie = &proto.Movie{}
+	err = repo.db.QueryRowContext(ctx, "SELECT id, title, year FROM movies WHERE id = $1", int64(req.Id)).
+		Scan(&movie.Id, &movie.Title, &movie.Year)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+
+	return movie, nil
+}
+
+func (repo *MoviesRepositoryImpl) Create(ctx context.Context, movie *proto.Movie) (created *proto.Movie, err error) {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { observe("create", start, err) }()
+
+	created = &proto.Movie{Title: movie.Title, Year: movie.Year}
+	err = repo.db.QueryRowContext(ctx,
+		"INSERT INTO movies (title, year) VALUES ($1, $2) RETURNING id",
+		movie.Title, movie.Year,
+	).Scan(&created.Id)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+
+	return created, nil
+}
+
+func (repo *MoviesRepositoryImpl) Delete(ctx context.Context, id uint32) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, writeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	defer func() { observe("delete", start, err) }()
+
+	result, err := repo.db.ExecContext(ctx, "DELETE FROM movies WHERE id = $1", int64(id))
+	if err != nil {
+		return TranslateError(err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return TranslateError(err)
+	}
+
+	if deleted == 0 {
+		return util.ErrMovieNotFound
+	}
+
+	return nil
+}
+
+func (repo *MoviesRepositoryImpl) query(ctx context.Context, query string, args ...any) ([]*proto.Movie, error) {
+	rows, err := repo.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, TranslateError(err)
+	}
+	defer rows.Close()
+
+	movies := []*proto.Movie{}
+	for rows.Next() {
+		movie := &proto.Movie{}
+		if err := rows.Scan(&movie.Id, &movie.Title, &movie.Year); err != nil {
+			return nil, TranslateError(err)
+		}
+		movies = append(movies, movie)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, TranslateError(err)
+	}
+
+	return movies, nil
+}
+
+// observe records the operation's latency. A missing movie is an answer,
+// not a storage failure, as in the Mongo repository.
+func observe(operation string, start time.Time, err error) {
+	if errors.Is(err, util.ErrMovieNotFound) {
+		err = nil
+	}
+	metrics.ObservePostgresOperation(operation, start, err)
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"movies/seed"
+)
+
+const syncSequence = "SELECT setval('movies_id_seq', COALESCE((SELECT MAX(id) FROM movies), 0) + 1, false)"
+
+type SeedStore struct {
+	db *sql.DB
+}
+
+var _ seed.Store = (*SeedStore)(nil)
+
+func NewSeedStore(db *sql.DB) *SeedStore {
+	return &SeedStore{db: db}
+}
+
+func (store *SeedStore) Empty(ctx context.Context) (bool, error) {
+	var empty bool
+	err := store.db.QueryRowContext(ctx, "SELECT NOT EXISTS (SELECT 1 FROM movies)").Scan(&empty)
+	return empty, err
+}
+
+func (store *SeedStore) Lookup(ctx context.Context, ids []uint32) (map[uint32]seed.Movie, error) {
+	params := make([]int64, len(ids))
+	for i, id := range ids {
+		params[i] = int64(id)
+	}
+
+	rows, err := store.db.QueryContext(ctx, "SELECT id, title, year FROM movies WHERE id = ANY($1)", params)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	existing := make(map[uint32]seed.Movie, len(ids))
+	for rows.Next() {
+		var movie seed.Movie
+		if err := rows.Scan(&movie.Id, &movie.Title, &movie.Year); err != nil {
+			return nil, err
+		}
+		existing[movie.Id] = movie
+	}
+
+	return existing, rows.Err()
+}
+
+// Write upserts the batch keeping the seed IDs, then moves the sequence
+// past the highest one so new movies continue from there.
+func (store *SeedStore) Write(ctx context.Context, movies []seed.Movie) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	upsert, err := tx.PrepareContext(ctx, `INSERT INTO movies (id, title, year) VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET title = EXCLUDED.title, year = EXCLUDED.year, updated_at = now()`)
+	if err != nil {
+		return err
+	}
+	defer upsert.Close()
+
+	for _, movie := range movies {
+		if _, err := upsert.ExecContext(ctx, int64(movie.Id), movie.Title, movie.Year); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, syncSequence); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
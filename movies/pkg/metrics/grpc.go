@@ -0,0 +1,26 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		grpcServerDuration.WithLabelValues(info.FullMethod, status.Code(err).String()).
+			Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.uber.org/zap"
+)
+
+const namespace = "movies"
+
+var registry = prometheus.NewRegistry()
+
+var grpcServerDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc_server",
+		Name:      "handling_seconds",
+		Help:      "Latency of gRPC calls handled by the movies service.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"method", "code"},
+)
+
+var mongoOperationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "mongo",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of MongoDB operations issued by the repository.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"operation", "status"},
+)
+
+var boltOperationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "bolt",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of embedded bbolt operations issued by the repository.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"operation", "status"},
+)
+
+var postgresOperationDuration = prometheus.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "postgres",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of PostgreSQL operations issued by the repository.",
+		Buckets:   prometheus.DefBuckets,
+	},
+	[]string{"operation", "status"},
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		grpcServerDuration,
+		mongoOperationDuration,
+		boltOperationDuration,
+		postgresOperationDuration,
+	)
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// NewServer builds the standalone /metrics server; the gRPC port cannot
+// serve plain HTTP scrapes. It also carries the internal admin routes,
+// since it's the port that never faces the public.
+func NewServer(port string, admin map[string]http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	for pattern, handler := range admin {
+		mux.Handle(pattern, handler)
+	}
+
+	return &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: mux,
+	}
+}
+
+// RequireToken only lets requests with "Authorization: Bearer <token>"
+// through to handler, for admin endpoints that change the service.
+func RequireToken(token string, handler http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func Serve(server *http.Server, log *zap.Logger) {
+	log.Info("Metrics server running", zap.String("address", server.Addr))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Error("Metrics server stopped", zap.Error(err))
+	}
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import "time"
+
+const (
+	statusOk    = "ok"
+	statusError = "error"
+)
+
+func ObserveMongoOperation(operation string, start time.Time, err error) {
+	mongoOperationDuration.WithLabelValues(operation, operationStatus(err)).
+		Observe(time.Since(start).Seconds())
+}
+
+func ObserveBoltOperation(operation string, start time.Time, err error) {
+	boltOperationDuration.WithLabelValues(operation, operationStatus(err)).
+		Observe(time.Since(start).Seconds())
+}
+
+func ObservePostgresOperation(operation string, start time.Time, err error) {
+	postgresOperationDuration.WithLabelValues(operation, operationStatus(err)).
+		Observe(time.Since(start).Seconds())
+}
+
+func operationStatus(err error) string {
+	if err != nil {
+		return statusError
+	}
+	return statusOk
+}
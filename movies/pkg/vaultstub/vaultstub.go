@@ -0,0 +1,133 @@
+// Package vaultstub is an in-memory stand-in for the parts of the Vault
+// HTTP API the secret provider uses: reading and writing key/value
+// secrets with a static token. It is for local development and tests, not
+// for keeping real secrets.
+package vaultstub
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Stub serves /v1/<path>. Paths with a /data/ segment answer in the KV v2
+// shape, with the values under data.data and a version under
+// data.metadata; anything else answers like KV v1.
+type Stub struct {
+	token string
+
+	mutex    sync.RWMutex
+	secrets  map[string]map[string]string
+	versions map[string]int
+}
+
+// New returns an empty stub. An empty token accepts every request.
+func New(token string) *Stub {
+	return &Stub{
+		token:    token,
+		secrets:  make(map[string]map[string]string),
+		versions: make(map[string]int),
+	}
+}
+
+// Put replaces the secret at path, bumping its version.
+func (stub *Stub) Put(path string, values map[string]string) {
+	path = strings.Trim(path, "/")
+
+	stub.mutex.Lock()
+	defer stub.mutex.Unlock()
+
+	copied := make(map[string]string, len(values))
+	for key, value := range values {
+		copied[key] = value
+	}
+	stub.secrets[path] = copied
+	stub.versions[path]++
+}
+
+func (stub *Stub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if stub.token != "" && r.Header.Get("X-Vault-Token") != stub.token {
+		writeErrors(w, http.StatusForbidden, "permission denied")
+		return
+	}
+
+	path, ok := strings.CutPrefix(r.URL.Path, "/v1/")
+	if !ok {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+	path = strings.Trim(path, "/")
+
+	switch r.Method {
+	case http.MethodGet:
+		stub.read(w, path)
+	case http.MethodPut, http.MethodPost:
+		stub.write(w, r, path)
+	default:
+		writeErrors(w, http.StatusMethodNotAllowed)
+	}
+}
+
+func (stub *Stub) read(w http.ResponseWriter, path string) {
+	stub.mutex.RLock()
+	values, found := stub.secrets[path]
+	version := stub.versions[path]
+	stub.mutex.RUnlock()
+
+	if !found {
+		writeErrors(w, http.StatusNotFound)
+		return
+	}
+
+	var data any = values
+	if isVersioned(path) {
+		data = map[string]any{
+			"data":     values,
+			"metadata": map[string]int{"version": version},
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
+func (stub *Stub) write(w http.ResponseWriter, r *http.Request, path string) {
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeErrors(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// KV v2 wraps the values in "data", KV v1 takes them as the body.
+	raw, err := json.Marshal(body)
+	if nested, ok := body["data"]; ok && isVersioned(path) {
+		raw, err = nested, nil
+	}
+
+	var values map[string]string
+	if err == nil {
+		err = json.Unmarshal(raw, &values)
+	}
+	if err != nil {
+		writeErrors(w, http.StatusBadRequest, "values must be strings")
+		return
+	}
+
+	stub.Put(path, values)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isVersioned(path string) bool {
+	return strings.Contains(path, "/data/")
+}
+
+func writeErrors(w http.ResponseWriter, status int, messages ...string) {
+	if messages == nil {
+		messages = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string][]string{"errors": messages})
+}
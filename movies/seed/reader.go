@@ -0,0 +1,243 @@
+package seed
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// embedded is the catalogue shipped with the service, used when no seed
+// path is configured so the binary doesn't depend on the source tree.
+//
+//go:embed movies.json
+var embedded []byte
+
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// DetectFormat picks the format from the file extension, defaulting to a
+// JSON array like the embedded seed.
+func DetectFormat(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ndjson", ".jsonl":
+		return FormatNDJSON
+	case ".csv":
+		return FormatCSV
+	default:
+		return FormatJSON
+	}
+}
+
+// Reader yields seed movies one at a time from a JSON array, NDJSON or CSV
+// stream. Next returns io.EOF after the last movie.
+type Reader struct {
+	// IDOptional lets records without an id through, for imports where
+	// the service assigns the IDs.
+	IDOptional bool
+
+	next   func() (Movie, error)
+	closer io.Closer
+	record int
+}
+
+// Open reads the seed at path, or the embedded seed when path is empty.
+// An empty format is detected from the path.
+func Open(path string, format Format) (*Reader, error) {
+	if path == "" {
+		return NewReader(bytes.NewReader(embedded), FormatJSON)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "" {
+		format = DetectFormat(path)
+	}
+
+	reader, err := NewReader(file, format)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	reader.closer = file
+	return reader, nil
+}
+
+func NewReader(r io.Reader, format Format) (*Reader, error) {
+	reader := &Reader{}
+	buffered := bufio.NewReader(r)
+
+	switch format {
+	case FormatJSON:
+		decoder := json.NewDecoder(buffered)
+		if err := expectDelim(decoder, '['); err != nil {
+			return nil, err
+		}
+		reader.next = func() (Movie, error) { return nextArrayElement(decoder) }
+	case FormatNDJSON:
+		decoder := json.NewDecoder(buffered)
+		reader.next = func() (Movie, error) { return nextJSON(decoder) }
+	case FormatCSV:
+		csvReader := csv.NewReader(buffered)
+		columns, err := csvColumns(csvReader)
+		if err != nil {
+			return nil, err
+		}
+		reader.next = func() (Movie, error) { return nextCSV(csvReader, columns) }
+	default:
+		return nil, fmt.Errorf("unknown seed format %q, expected %q, %q or %q", format, FormatJSON, FormatNDJSON, FormatCSV)
+	}
+
+	return reader, nil
+}
+
+func (reader *Reader) Next() (Movie, error) {
+	movie, err := reader.next()
+	if err == io.EOF {
+		return Movie{}, err
+	}
+
+	reader.record++
+	if err == nil && movie.Id == 0 && !reader.IDOptional {
+		err = errors.New("missing id")
+	}
+	if err != nil {
+		return Movie{}, fmt.Errorf("seed record %d: %w", reader.record, err)
+	}
+	return movie, nil
+}
+
+func (reader *Reader) Close() error {
+	if reader.closer == nil {
+		return nil
+	}
+	return reader.closer.Close()
+}
+
+// jsonRecord is the shape of a movie in JSON and NDJSON seed files.
+type jsonRecord struct {
+	Id    uint32 `json:"id"`
+	Title string `json:"title"`
+	Year  string `json:"year"`
+}
+
+// jsonMovie accepts the year as a string, like the seed file, or a number.
+type jsonMovie struct {
+	Id    uint32          `json:"id"`
+	Title string          `json:"title"`
+	Year  json.RawMessage `json:"year"`
+}
+
+func (movie jsonMovie) seed() (Movie, error) {
+	year := string(movie.Year)
+	switch {
+	case year == "null":
+		year = ""
+	case strings.HasPrefix(year, `"`):
+		if err := json.Unmarshal(movie.Year, &year); err != nil {
+			return Movie{}, err
+		}
+	}
+	return Movie{Id: movie.Id, Title: movie.Title, Year: year}, nil
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("seed: expected %q, found %v", delim, token)
+	}
+	return nil
+}
+
+func nextArrayElement(decoder *json.Decoder) (Movie, error) {
+	if !decoder.More() {
+		if err := expectDelim(decoder, ']'); err != nil {
+			return Movie{}, err
+		}
+		return Movie{}, io.EOF
+	}
+	return nextJSON(decoder)
+}
+
+func nextJSON(decoder *json.Decoder) (Movie, error) {
+	var movie jsonMovie
+	if err := decoder.Decode(&movie); err != nil {
+		return Movie{}, err
+	}
+	return movie.seed()
+}
+
+type csvIndex struct {
+	id, title, year int
+}
+
+// csvColumns reads the header row; columns may come in any order, extra
+// ones are ignored and id may be left out when the reader allows it.
+func csvColumns(reader *csv.Reader) (csvIndex, error) {
+	header, err := reader.Read()
+	if err != nil {
+		return csvIndex{}, fmt.Errorf("seed: reading CSV header: %w", err)
+	}
+
+	columns := csvIndex{id: -1, title: -1, year: -1}
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "id":
+			columns.id = i
+		case "title":
+			columns.title = i
+		case "year":
+			columns.year = i
+		}
+	}
+
+	if columns.title < 0 || columns.year < 0 {
+		return csvIndex{}, errors.New("seed: CSV header must name title and year columns")
+	}
+
+	reader.FieldsPerRecord = len(header)
+	reader.ReuseRecord = true
+	return columns, nil
+}
+
+func nextCSV(reader *csv.Reader, columns csvIndex) (Movie, error) {
+	row, err := reader.Read()
+	if err != nil {
+		return Movie{}, err
+	}
+
+	movie := Movie{Title: row[columns.title], Year: strings.TrimSpace(row[columns.year])}
+	if columns.id < 0 {
+		return movie, nil
+	}
+
+	if value := strings.TrimSpace(row[columns.id]); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return Movie{}, fmt.Errorf("invalid id %q", value)
+		}
+		movie.Id = uint32(id)
+	}
+
+	return movie, nil
+}
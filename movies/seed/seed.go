@@ -0,0 +1,139 @@
+// Package seed loads the initial movie catalogue into any storage backend.
+// Seed files are streamed record by record, so large catalogues never sit
+// in memory, and applied in batches through the backend's Store.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+const batchSize = 1000
+
+type Mode string
+
+const (
+	// ModeIfEmpty seeds only a store with no movies, the historical behaviour.
+	ModeIfEmpty Mode = "if-empty"
+	// ModeUpsert inserts missing movies and updates changed ones by ID, so
+	// seed updates reach databases that were seeded before.
+	ModeUpsert Mode = "upsert"
+	ModeOff    Mode = "off"
+)
+
+func ParseMode(value string) (Mode, error) {
+	switch mode := Mode(value); mode {
+	case ModeIfEmpty, ModeUpsert, ModeOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown seed mode %q, expected %q, %q or %q", value, ModeIfEmpty, ModeUpsert, ModeOff)
+	}
+}
+
+type Movie struct {
+	Id    uint32
+	Title string
+	Year  string
+}
+
+// Store is what a backend implements to be seeded. Write must upsert by
+// ID and keep the backend's ID allocation ahead of the written IDs.
+type Store interface {
+	Empty(ctx context.Context) (bool, error)
+	Lookup(ctx context.Context, ids []uint32) (map[uint32]Movie, error)
+	Write(ctx context.Context, movies []Movie) error
+}
+
+type Options struct {
+	Mode   Mode
+	DryRun bool
+}
+
+// Report counts what Apply did, or with DryRun what it would have done.
+type Report struct {
+	Skipped   bool
+	DryRun    bool
+	Read      int
+	Inserted  int
+	Updated   int
+	Unchanged int
+}
+
+// Apply streams every movie from reader into store according to opts.
+func Apply(ctx context.Context, store Store, reader *Reader, opts Options) (Report, error) {
+	report := Report{DryRun: opts.DryRun}
+
+	switch opts.Mode {
+	case ModeOff:
+		report.Skipped = true
+		return report, nil
+	case ModeIfEmpty:
+		empty, err := store.Empty(ctx)
+		if err != nil {
+			return report, err
+		}
+		if !empty {
+			report.Skipped = true
+			return report, nil
+		}
+	}
+
+	batch := make([]Movie, 0, batchSize)
+	for {
+		movie, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+
+		report.Read++
+		batch = append(batch, movie)
+		if len(batch) == batchSize {
+			if err := apply(ctx, store, batch, opts, &report); err != nil {
+				return report, err
+			}
+			batch = batch[:0]
+		}
+	}
+
+	return report, apply(ctx, store, batch, opts, &report)
+}
+
+func apply(ctx context.Context, store Store, batch []Movie, opts Options, report *Report) error {
+	if len(batch) == 0 {
+		return nil
+	}
+
+	ids := make([]uint32, len(batch))
+	for i, movie := range batch {
+		ids[i] = movie.Id
+	}
+
+	existing, err := store.Lookup(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	writes := make([]Movie, 0, len(batch))
+	for _, movie := range batch {
+		current, found := existing[movie.Id]
+		switch {
+		case !found:
+			report.Inserted++
+		case current != movie:
+			report.Updated++
+		default:
+			report.Unchanged++
+			continue
+		}
+		writes = append(writes, movie)
+	}
+
+	if opts.DryRun || len(writes) == 0 {
+		return nil
+	}
+	return store.Write(ctx, writes)
+}
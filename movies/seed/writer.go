@@ -0,0 +1,82 @@
+package seed
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Writer produces seed files that Reader reads back, so exports can be
+// replayed as seeds or imports. Close must be called to finish the file.
+type Writer struct {
+	format  Format
+	out     *bufio.Writer
+	csv     *csv.Writer
+	written int
+}
+
+func NewWriter(w io.Writer, format Format) (*Writer, error) {
+	writer := &Writer{format: format, out: bufio.NewWriter(w)}
+
+	switch format {
+	case FormatJSON:
+		if _, err := writer.out.WriteString("["); err != nil {
+			return nil, err
+		}
+	case FormatNDJSON:
+	case FormatCSV:
+		writer.csv = csv.NewWriter(writer.out)
+		if err := writer.csv.Write([]string{"id", "title", "year"}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown seed format %q, expected %q, %q or %q", format, FormatJSON, FormatNDJSON, FormatCSV)
+	}
+
+	return writer, nil
+}
+
+func (writer *Writer) Write(movie Movie) error {
+	if writer.csv != nil {
+		writer.written++
+		return writer.csv.Write([]string{strconv.FormatUint(uint64(movie.Id), 10), movie.Title, movie.Year})
+	}
+
+	data, err := json.Marshal(jsonRecord{Id: movie.Id, Title: movie.Title, Year: movie.Year})
+	if err != nil {
+		return err
+	}
+
+	prefix, suffix := "", "\n"
+	if writer.format == FormatJSON {
+		prefix, suffix = ",\n    ", ""
+		if writer.written == 0 {
+			prefix = "\n    "
+		}
+	}
+
+	writer.written++
+	writer.out.WriteString(prefix)
+	writer.out.Write(data)
+	_, err = writer.out.WriteString(suffix)
+	return err
+}
+
+func (writer *Writer) Close() error {
+	switch {
+	case writer.csv != nil:
+		writer.csv.Flush()
+		if err := writer.csv.Error(); err != nil {
+			return err
+		}
+	case writer.format == FormatJSON && writer.written > 0:
+		writer.out.WriteString("\n]\n")
+	case writer.format == FormatJSON:
+		writer.out.WriteString("]\n")
+	}
+
+	return writer.out.Flush()
+}
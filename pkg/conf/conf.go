@@ -0,0 +1,341 @@
+// Package conf fills a configuration struct from layered sources. Each
+// field names its environment variable in an `env` tag; the same name in
+// lower case is its key in the config file and, with dashes, its flag:
+//
+//	DbName string `env:"MONGO_DB" default:"movies" validate:"required"`
+//
+// is set by default to "movies", by `mongo_db: ...` in the file, by
+// MONGO_DB in the environment and by -mongo-db on the command line, each
+// layer overriding the one before. Fields tagged `secret:"true"` are
+// redacted in Settings, and their environment layer is a chain of
+// SecretProviders: the variable, a KEY_FILE mount, then Vault.
+package conf
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+
+	// FileEnv and the -config flag point at the optional config file.
+	FileEnv = "CONFIG_FILE"
+)
+
+// ErrPrintedConfig is returned by Load once -print-config has written the
+// settings; the caller should exit successfully without starting.
+var ErrPrintedConfig = errors.New("conf: configuration printed")
+
+type Options struct {
+	// Args are the command-line arguments without the program name. Nil
+	// disables the flag layer, for tools that parse their own flags.
+	Args []string
+	// LookupEnv defaults to os.LookupEnv.
+	LookupEnv func(key string) (string, bool)
+	// Output receives -help and -print-config; defaults to os.Stderr and
+	// os.Stdout respectively.
+	Output io.Writer
+	// Secrets resolve the secret fields in place of the environment layer.
+	// Nil means SecretsFromEnv(LookupEnv).
+	Secrets []SecretProvider
+}
+
+// Validator is implemented by configs with rules spanning several fields.
+// It runs after every field has been loaded and checked.
+type Validator interface {
+	Validate() error
+}
+
+type field struct {
+	key    string
+	value  reflect.Value
+	secret bool
+	rules  string
+	def    string
+}
+
+// Load fills the struct dst points to and returns its effective settings.
+// Every problem found is reported at once, one per line. With
+// -print-config it prints them and returns ErrPrintedConfig.
+func Load(dst any, opts Options) (Settings, error) {
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	if opts.Secrets == nil {
+		secrets, err := SecretsFromEnv(opts.LookupEnv)
+		if err != nil {
+			return nil, err
+		}
+		opts.Secrets = secrets
+	}
+
+	fields, err := fieldsOf(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := make(map[string]string, len(fields))
+	sources := make(map[string]string, len(fields))
+	set := func(key, value, source string) {
+		raw[key] = value
+		sources[key] = source
+	}
+
+	for _, field := range fields {
+		set(field.key, field.def, SourceDefault)
+	}
+
+	flags, printConfig, configFlag := newFlagSet(fields, opts)
+	if opts.Args != nil {
+		if err := flags.Parse(opts.Args); err != nil {
+			return nil, err
+		}
+	}
+
+	path, _ := opts.LookupEnv(FileEnv)
+	if *configFlag != "" {
+		path = *configFlag
+	}
+
+	var problems []error
+	if path != "" {
+		values, err := readFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: %w", path, err)
+		}
+
+		for key, value := range values {
+			envKey := strings.ToUpper(key)
+			if _, known := raw[envKey]; !known {
+				problems = append(problems, fmt.Errorf("config file %s: unknown key %q", path, key))
+				continue
+			}
+			set(envKey, value, SourceFile)
+		}
+	}
+
+	// Empty variables count as unset: compose passes `KEY: ${KEY}` through
+	// even when KEY isn't defined.
+	resolveCtx := withResolvePass(context.Background())
+	for _, field := range fields {
+		if field.secret {
+			value, source, err := Resolve(resolveCtx, opts.Secrets, field.key)
+			if err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", field.key, err))
+			} else if source != "" {
+				set(field.key, value, source)
+			}
+			continue
+		}
+
+		if value, ok := opts.LookupEnv(field.key); ok && value != "" {
+			set(field.key, value, SourceEnv)
+		}
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		if key := flagKey(f.Name); key != "" {
+			set(key, f.Value.String(), SourceFlag)
+		}
+	})
+
+	settings := make(Settings, 0, len(fields))
+	for _, field := range fields {
+		value, source := raw[field.key], sources[field.key]
+		settings = append(settings, Setting{Key: field.key, Value: redact(value, field.secret), Source: source, Secret: field.secret})
+
+		if err := assign(field.value, value); err != nil {
+			problems = append(problems, fmt.Errorf("%s (%s): %w", field.key, source, err))
+			continue
+		}
+		if err := check(field.rules, value); err != nil {
+			problems = append(problems, fmt.Errorf("%s (%s): %w", field.key, source, err))
+		}
+	}
+
+	if len(problems) == 0 {
+		if validator, ok := dst.(Validator); ok {
+			if err := validator.Validate(); err != nil {
+				if joined, ok := err.(interface{ Unwrap() []error }); ok {
+					problems = append(problems, joined.Unwrap()...)
+				} else {
+					problems = append(problems, err)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return settings, &Error{Problems: problems}
+	}
+
+	if *printConfig {
+		settings.Print(output(opts.Output, os.Stdout))
+		return settings, ErrPrintedConfig
+	}
+
+	return settings, nil
+}
+
+// RefreshSecrets resolves the secret fields of dst again, for callers that
+// poll for rotated credentials, and reports whether any of them changed.
+// Fields given as a flag keep that value, as they would in Load.
+func RefreshSecrets(ctx context.Context, dst any, settings Settings, providers []SecretProvider) (bool, error) {
+	fields, err := fieldsOf(dst)
+	if err != nil {
+		return false, err
+	}
+
+	sources := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		sources[setting.Key] = setting.Source
+	}
+
+	ctx = withResolvePass(ctx)
+	changed := false
+	for _, field := range fields {
+		if !field.secret || sources[field.key] == SourceFlag {
+			continue
+		}
+
+		value, source, err := Resolve(ctx, providers, field.key)
+		if err != nil {
+			return false, fmt.Errorf("%s: %w", field.key, err)
+		}
+		if source == "" || value == field.value.String() {
+			continue
+		}
+
+		if err := assign(field.value, value); err != nil {
+			return false, fmt.Errorf("%s (%s): %w", field.key, source, err)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// Error lists every invalid setting found by Load.
+type Error struct {
+	Problems []error
+}
+
+func (e *Error) Error() string {
+	lines := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		lines[i] = "  " + problem.Error()
+	}
+	return "invalid configuration:\n" + strings.Join(lines, "\n")
+}
+
+func (e *Error) Unwrap() []error {
+	return e.Problems
+}
+
+func fieldsOf(dst any) ([]field, error) {
+	value := reflect.ValueOf(dst)
+	if value.Kind() != reflect.Pointer || value.Elem().Kind() != reflect.Struct {
+		return nil, errors.New("conf: Load needs a pointer to a struct")
+	}
+
+	value = value.Elem()
+	var fields []field
+	for i := 0; i < value.NumField(); i++ {
+		structField := value.Type().Field(i)
+		key := structField.Tag.Get("env")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		fields = append(fields, field{
+			key:    key,
+			value:  value.Field(i),
+			secret: structField.Tag.Get("secret") == "true",
+			rules:  structField.Tag.Get("validate"),
+			def:    structField.Tag.Get("default"),
+		})
+	}
+	return fields, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func assign(target reflect.Value, value string) error {
+	switch {
+	case target.Type() == durationType:
+		if value == "" {
+			target.SetInt(0)
+			return nil
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", value)
+		}
+		target.SetInt(int64(duration))
+	case target.Kind() == reflect.String:
+		target.SetString(value)
+	case target.Kind() == reflect.Bool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		target.SetBool(parsed)
+	case target.Kind() == reflect.Int:
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		target.SetInt(int64(parsed))
+	default:
+		return fmt.Errorf("conf: unsupported field type %s", target.Type())
+	}
+	return nil
+}
+
+func flagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// flagKey maps a flag back to its env key, or "" for -config and friends.
+func flagKey(name string) string {
+	switch name {
+	case "config", "print-config":
+		return ""
+	}
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+func newFlagSet(fields []field, opts Options) (*flag.FlagSet, *bool, *string) {
+	flags := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	flags.SetOutput(output(opts.Output, os.Stderr))
+
+	configFile := flags.String("config", "", "YAML or TOML config file ("+FileEnv+")")
+	printConfig := flags.Bool("print-config", false, "print the effective configuration, secrets redacted, and exit")
+	for _, field := range fields {
+		usage := field.key
+		if field.secret {
+			usage += ", secret"
+		}
+		flags.String(flagName(field.key), field.def, usage)
+	}
+
+	return flags, printConfig, configFile
+}
+
+func output(w io.Writer, fallback io.Writer) io.Writer {
+	if w != nil {
+		return w
+	}
+	return fallback
+}
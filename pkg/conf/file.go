@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// readFile parses a YAML or TOML file, picked by extension, into flat
+// lower-case keys. Nested tables are joined with "_", so
+//
+//	mongo:
+//	  db: movies
+//
+// sets mongo_db like a top-level `mongo_db: movies` would.
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var document map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &document)
+	case ".toml":
+		err = toml.Unmarshal(data, &document)
+	default:
+		return nil, fmt.Errorf("unknown format, expected .yaml, .yml or .toml")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	flatten("", document, values)
+	return values, nil
+}
+
+func flatten(prefix string, document map[string]any, values map[string]string) {
+	for key, value := range document {
+		key = strings.ToLower(key)
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			flatten(key, value, values)
+		case nil:
+			values[key] = ""
+		default:
+			values[key] = fmt.Sprint(value)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package conf
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SecretProvider supplies the values of fields tagged `secret:"true"`.
+// Load asks each provider in turn and keeps the first value found; flags
+// still override it. Name is recorded as the setting's source.
+type SecretProvider interface {
+	Name() string
+	Lookup(ctx context.Context, key string) (value string, found bool, err error)
+}
+
+// SecretsFromEnv is the provider chain Load uses by default: the variable
+// itself, then a file named by KEY_FILE, then Vault when VAULT_ADDR is set.
+func SecretsFromEnv(lookupEnv func(string) (string, bool)) ([]SecretProvider, error) {
+	providers := []SecretProvider{EnvSecrets{LookupEnv: lookupEnv}, FileSecrets{LookupEnv: lookupEnv}}
+
+	addr, _ := lookupEnv("VAULT_ADDR")
+	if addr == "" {
+		return providers, nil
+	}
+
+	token, _, err := Resolve(context.Background(), providers, "VAULT_TOKEN")
+	if err != nil {
+		return nil, err
+	}
+	path, _ := lookupEnv("VAULT_SECRET_PATH")
+	if path == "" {
+		return nil, fmt.Errorf("VAULT_SECRET_PATH is required when VAULT_ADDR is set")
+	}
+
+	return append(providers, NewVaultSecrets(addr, token, path)), nil
+}
+
+// Resolve asks providers for key in order and returns the first value
+// found with the name of the provider that had it. An empty source means
+// no provider knows the key.
+func Resolve(ctx context.Context, providers []SecretProvider, key string) (value, source string, err error) {
+	for _, provider := range providers {
+		value, found, err := provider.Lookup(ctx, key)
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", provider.Name(), err)
+		}
+		if found {
+			return value, provider.Name(), nil
+		}
+	}
+	return "", "", nil
+}
+
+// EnvSecrets reads the secret from its own variable, as before secret
+// providers existed.
+type EnvSecrets struct {
+	LookupEnv func(string) (string, bool)
+}
+
+func (EnvSecrets) Name() string {
+	return SourceEnv
+}
+
+func (secrets EnvSecrets) Lookup(_ context.Context, key string) (string, bool, error) {
+	value, ok := secrets.LookupEnv(key)
+	return value, ok && value != "", nil
+}
+
+// FileSecrets reads the secret from the file named by KEY_FILE, the way
+// docker and Kubernetes secret mounts are usually wired. A trailing newline
+// is dropped. The file is read on every lookup, so a rotated mount is seen
+// on the next reload.
+type FileSecrets struct {
+	LookupEnv func(string) (string, bool)
+}
+
+func (FileSecrets) Name() string {
+	return "secret-file"
+}
+
+func (secrets FileSecrets) Lookup(_ context.Context, key string) (string, bool, error) {
+	path, ok := secrets.LookupEnv(key + "_FILE")
+	if !ok || path == "" {
+		return "", false, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %w", key, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
+
+// VaultSecrets reads secrets from one key/value secret over the Vault HTTP
+// API, keyed by the setting name (MONGO_DB_PASSWORD). Path is everything
+// after /v1/, e.g. secret/data/movies for the KV v2 engine mounted at
+// secret; KV v1 responses are understood as well.
+type VaultSecrets struct {
+	Addr   string
+	Token  string
+	Path   string
+	Client *http.Client
+}
+
+func NewVaultSecrets(addr, token, path string) *VaultSecrets {
+	return &VaultSecrets{
+		Addr:   strings.TrimRight(addr, "/"),
+		Token:  token,
+		Path:   strings.Trim(path, "/"),
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+func (*VaultSecrets) Name() string {
+	return "vault"
+}
+
+func (secrets *VaultSecrets) Lookup(ctx context.Context, key string) (string, bool, error) {
+	data, err := secrets.readOnce(ctx)
+	if err != nil {
+		return "", false, err
+	}
+
+	value, ok := data[key]
+	return value, ok, nil
+}
+
+// readOnce fetches the secret path once per resolve pass; outside a pass
+// every lookup reads it again.
+func (secrets *VaultSecrets) readOnce(ctx context.Context) (map[string]string, error) {
+	pass, ok := ctx.Value(passKey{}).(*resolvePass)
+	if !ok {
+		return secrets.read(ctx)
+	}
+
+	pass.mutex.Lock()
+	defer pass.mutex.Unlock()
+
+	if read, ok := pass.reads[secrets]; ok {
+		return read.data, read.err
+	}
+	data, err := secrets.read(ctx)
+	pass.reads[secrets] = passRead{data: data, err: err}
+	return data, err
+}
+
+func (secrets *VaultSecrets) read(ctx context.Context) (map[string]string, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, secrets.Addr+"/v1/"+secrets.Path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if secrets.Token != "" {
+		request.Header.Set("X-Vault-Token", secrets.Token)
+	}
+
+	response, err := secrets.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", secrets.Path, response.Status)
+	}
+
+	var body struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("GET %s: %w", secrets.Path, err)
+	}
+
+	// KV v2 nests the values under data.data next to data.metadata.
+	var versioned struct {
+		Data     map[string]string `json:"data"`
+		Metadata json.RawMessage   `json:"metadata"`
+	}
+	if err := json.Unmarshal(body.Data, &versioned); err == nil && versioned.Metadata != nil {
+		return versioned.Data, nil
+	}
+
+	var flat map[string]string
+	if err := json.Unmarshal(body.Data, &flat); err != nil {
+		return nil, fmt.Errorf("GET %s: values must be strings: %w", secrets.Path, err)
+	}
+	return flat, nil
+}
+
+type passKey struct{}
+
+type passRead struct {
+	data map[string]string
+	err  error
+}
+
+// resolvePass remembers what providers fetched while Load or
+// RefreshSecrets resolve every secret field, so Vault is asked once per
+// pass instead of once per key.
+type resolvePass struct {
+	mutex sync.Mutex
+	reads map[*VaultSecrets]passRead
+}
+
+func withResolvePass(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(passKey{}).(*resolvePass); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, passKey{}, &resolvePass{reads: map[*VaultSecrets]passRead{}})
+}
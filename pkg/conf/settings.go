@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"text/tabwriter"
+)
+
+const redacted = "[redacted]"
+
+// Setting is one effective value and the layer it came from. Secret
+// values are already redacted.
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Secret bool   `json:"secret,omitempty"`
+}
+
+// Settings is the effective configuration, safe to log or serve.
+type Settings []Setting
+
+func (settings Settings) Print(w io.Writer) {
+	writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(writer, "KEY\tVALUE\tSOURCE")
+	for _, setting := range settings {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", setting.Key, setting.Value, setting.Source)
+	}
+	writer.Flush()
+}
+
+// Handler serves the settings as JSON for the admin endpoint.
+func (settings Settings) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(settings)
+	})
+}
+
+// redact hides a secret value. URLs keep everything but the password, so
+// a connection string still shows where it points.
+func redact(value string, secret bool) string {
+	if !secret || value == "" {
+		return value
+	}
+
+	if parsed, err := url.Parse(value); err == nil && parsed.Scheme != "" && parsed.Host != "" {
+		return parsed.Redacted()
+	}
+
+	return redacted
+}
@@ -0,0 +1,73 @@
+package conf
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// check applies the comma separated rules of a `validate` tag to the raw
+// value. Rules other than required pass on empty values, so optional
+// settings only need to be well formed when given:
+//
+//	required      must not be empty
+//	oneof=a|b|c   one of the listed values
+//	port          TCP port number
+//	url           absolute URL with a scheme and host
+//	min=n, max=n  integer bounds
+func check(rules, value string) error {
+	if rules == "" {
+		return nil
+	}
+
+	for _, rule := range strings.Split(rules, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		if name == "required" {
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("is required")
+			}
+			continue
+		}
+		if value == "" {
+			continue
+		}
+
+		switch name {
+		case "oneof":
+			options := strings.Split(arg, "|")
+			found := false
+			for _, option := range options {
+				found = found || option == value
+			}
+			if !found {
+				return fmt.Errorf("must be one of %s, got %q", strings.Join(options, ", "), value)
+			}
+		case "port":
+			port, err := strconv.Atoi(value)
+			if err != nil || port < 1 || port > 65535 {
+				return fmt.Errorf("must be a port number, got %q", value)
+			}
+		case "url":
+			parsed, err := url.Parse(value)
+			if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+				return fmt.Errorf("must be an absolute URL")
+			}
+		case "min", "max":
+			limit, _ := strconv.Atoi(arg)
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				continue
+			}
+			if name == "min" && number < limit {
+				return fmt.Errorf("must be at least %d, got %d", limit, number)
+			}
+			if name == "max" && number > limit {
+				return fmt.Errorf("must be at most %d, got %d", limit, number)
+			}
+		default:
+			return fmt.Errorf("conf: unknown rule %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Levels decides which entries are written, per named logger. Loggers
+// without a level of their own use the closest parent's (mongodb for
+// mongodb.migrate), then the root level.
+type Levels struct {
+	root zap.AtomicLevel
+
+	mutex sync.RWMutex
+	named map[string]zap.AtomicLevel
+}
+
+func newLevels(root zapcore.Level) *Levels {
+	return &Levels{root: zap.NewAtomicLevelAt(root), named: make(map[string]zap.AtomicLevel)}
+}
+
+// Root is the level of every logger without one of its own.
+func (levels *Levels) Root() zap.AtomicLevel {
+	return levels.root
+}
+
+func (levels *Levels) Set(name string, level zapcore.Level) {
+	levels.mutex.Lock()
+	defer levels.mutex.Unlock()
+
+	if current, ok := levels.named[name]; ok {
+		current.SetLevel(level)
+		return
+	}
+	levels.named[name] = zap.NewAtomicLevelAt(level)
+}
+
+// Reset drops name's own level, so it follows its parent again.
+func (levels *Levels) Reset(name string) {
+	levels.mutex.Lock()
+	defer levels.mutex.Unlock()
+	delete(levels.named, name)
+}
+
+// Level is the effective level of the logger called name.
+func (levels *Levels) Level(name string) zapcore.Level {
+	levels.mutex.RLock()
+	defer levels.mutex.RUnlock()
+
+	for name != "" {
+		if level, ok := levels.named[name]; ok {
+			return level.Level()
+		}
+		cut := strings.LastIndexByte(name, '.')
+		if cut < 0 {
+			break
+		}
+		name = name[:cut]
+	}
+	return levels.root.Level()
+}
+
+// lowest is the most verbose level any logger is at, for the fast path
+// that runs before the logger name is known.
+func (levels *Levels) lowest() zapcore.Level {
+	levels.mutex.RLock()
+	defer levels.mutex.RUnlock()
+
+	lowest := levels.root.Level()
+	for _, level := range levels.named {
+		lowest = min(lowest, level.Level())
+	}
+	return lowest
+}
+
+// ParseLevels reads per-logger levels written as "mongodb=debug,access=warn".
+func ParseLevels(spec string) (map[string]zapcore.Level, error) {
+	parsed := make(map[string]zapcore.Level)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, text, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid logger level %q, expected name=level", pair)
+		}
+		level, err := zapcore.ParseLevel(text)
+		if err != nil {
+			return nil, err
+		}
+		parsed[name] = level
+	}
+	return parsed, nil
+}
+
+// Handler serves zap's level endpoint for the root level, and the same
+// JSON for one named logger with ?logger=name:
+//
+//	GET    /log/level?logger=mongodb                    {"level":"info"}
+//	PUT    /log/level?logger=mongodb  {"level":"debug"}  {"level":"debug"}
+//	DELETE /log/level?logger=mongodb                    back to the parent's level
+//
+// Mount it behind authentication, it changes what the service logs.
+func (levels *Levels) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("logger")
+		if name == "" {
+			levels.root.ServeHTTP(w, r)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var request struct {
+				Level *zapcore.Level `json:"level"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.Level == nil {
+				writeLevel(w, http.StatusBadRequest, map[string]string{"error": "request body must be {\"level\": \"...\"}"})
+				return
+			}
+			levels.Set(name, *request.Level)
+		case http.MethodDelete:
+			levels.Reset(name)
+		default:
+			writeLevel(w, http.StatusMethodNotAllowed, map[string]string{"error": "only GET, PUT and DELETE are supported"})
+			return
+		}
+
+		writeLevel(w, http.StatusOK, map[string]string{"level": levels.Level(name).String()})
+	})
+}
+
+func writeLevel(w http.ResponseWriter, status int, body map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// levelCore filters entries by the level of the logger that wrote them.
+type levelCore struct {
+	zapcore.Core
+	levels *Levels
+}
+
+func (core *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= core.levels.lowest()
+}
+
+func (core *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: core.Core.With(fields), levels: core.levels}
+}
+
+func (core *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < core.levels.Level(entry.LoggerName) {
+		return checked
+	}
+	return core.Core.Check(entry, checked)
+}
@@ -0,0 +1,203 @@
+package logger
+
+import (
+	"context"
+	"pkg/requestid"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// Options tune the logger New builds for an environment. The zero value
+// logs everything from debug up in development and from info up in
+// production, to stdout only.
+type Options struct {
+	// Level overrides the environment's root level.
+	Level string
+	// Levels gives named loggers their own level, see ParseLevels.
+	Levels string
+
+	// File, when set, also receives every entry as JSON, rotated once it
+	// reaches FileMaxSizeMB. Old files are kept up to FileMaxBackups of
+	// them and FileMaxAgeDays days, 0 meaning no limit.
+	File           string
+	FileMaxSizeMB  int
+	FileMaxBackups int
+	FileMaxAgeDays int
+}
+
+// New builds the root logger and the Levels it filters by. Components log
+// through log.Named("mongodb") and friends so their level can be changed
+// on its own, at startup through Options.Levels or at runtime through
+// Levels.Handler.
+func New(env string, opts Options) (*zap.Logger, *Levels) {
+	var cfg zap.Config
+
+	if env == "production" {
+		cfg = zap.Config{
+			Level:       zap.NewAtomicLevelAt(zapcore.InfoLevel),
+			Encoding:    "json",
+			OutputPaths: []string{"stdout"},
+			EncoderConfig: zapcore.EncoderConfig{
+				TimeKey:        "timestamp",
+				LevelKey:       "level",
+				NameKey:        "logger",
+				CallerKey:      "caller",
+				MessageKey:     "message",
+				StacktraceKey:  "stacktrace",
+				LineEnding:     zapcore.DefaultLineEnding,
+				EncodeLevel:    zapcore.CapitalLevelEncoder,
+				EncodeTime:     zapcore.ISO8601TimeEncoder,
+				EncodeDuration: zapcore.StringDurationEncoder,
+				EncodeCaller:   zapcore.ShortCallerEncoder,
+			},
+		}
+	} else {
+		cfg = zap.Config{
+			Level:       zap.NewAtomicLevelAt(zapcore.DebugLevel),
+			Development: true,
+			Encoding:    "console",
+			OutputPaths: []string{"stdout"},
+			EncoderConfig: zapcore.EncoderConfig{
+				TimeKey:        "T",
+				LevelKey:       "L",
+				NameKey:        "N",
+				CallerKey:      "C",
+				MessageKey:     "M",
+				LineEnding:     zapcore.DefaultLineEnding,
+				EncodeLevel:    zapcore.CapitalColorLevelEncoder,
+				EncodeTime:     zapcore.ISO8601TimeEncoder,
+				EncodeDuration: zapcore.StringDurationEncoder,
+				EncodeCaller:   zapcore.ShortCallerEncoder,
+			},
+		}
+	}
+
+	var problems []error
+	root := cfg.Level.Level()
+	if opts.Level != "" {
+		level, err := zapcore.ParseLevel(opts.Level)
+		if err != nil {
+			problems = append(problems, err)
+		} else {
+			root = level
+		}
+	}
+
+	levels := newLevels(root)
+	named, err := ParseLevels(opts.Levels)
+	if err != nil {
+		problems = append(problems, err)
+	}
+	for name, level := range named {
+		levels.Set(name, level)
+	}
+
+	// The cores let everything through; levelCore does the filtering.
+	cfg.Level = zap.NewAtomicLevelAt(zapcore.DebugLevel)
+	logger, err := cfg.Build(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if opts.File != "" {
+			core = zapcore.NewTee(core, fileCore(cfg.EncoderConfig, opts))
+		}
+		return &levelCore{Core: core, levels: levels}
+	}))
+	if err != nil {
+		fallback := zap.NewExample()
+		fallback.Error("Failed to build zap logger", zap.Error(err))
+		return fallback, levels
+	}
+
+	for _, problem := range problems {
+		logger.Warn("Ignoring invalid log level", zap.Error(problem))
+	}
+
+	zap.ReplaceGlobals(logger)
+	return logger, levels
+}
+
+// fileCore writes JSON to a lumberjack logger, which rotates the file by
+// size on its own.
+func fileCore(encoderConfig zapcore.EncoderConfig, opts Options) zapcore.Core {
+	encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	writer := &lumberjack.Logger{
+		Filename:   opts.File,
+		MaxSize:    opts.FileMaxSizeMB,
+		MaxBackups: opts.FileMaxBackups,
+		MaxAge:     opts.FileMaxAgeDays,
+		Compress:   true,
+	}
+
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), zapcore.AddSync(writer), zapcore.DebugLevel)
+}
+
+// Sample thins out log's entries below warn to the first initial per
+// message each second, then every thereafter-th one, so a busy endpoint
+// can't flood the output. Warnings and errors are always written. A zero
+// thereafter leaves log as it is.
+func Sample(log *zap.Logger, initial, thereafter int) *zap.Logger {
+	if thereafter <= 0 {
+		return log
+	}
+
+	return log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &sampledCore{
+			Core:    core,
+			sampled: zapcore.NewSamplerWithOptions(core, time.Second, initial, thereafter),
+		}
+	}))
+}
+
+type sampledCore struct {
+	zapcore.Core
+	sampled zapcore.Core
+}
+
+func (core *sampledCore) With(fields []zapcore.Field) zapcore.Core {
+	// The sampler's With keeps sharing its counters, so per-request loggers
+	// are sampled together.
+	return &sampledCore{Core: core.Core.With(fields), sampled: core.sampled.With(fields)}
+}
+
+func (core *sampledCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if entry.Level < zapcore.WarnLevel {
+		return core.sampled.Check(entry, checked)
+	}
+	return core.Core.Check(entry, checked)
+}
+
+func Sync(logger *zap.Logger) {
+	_ = logger.Sync()
+}
+
+func InitGlobal(env string) *zap.Logger {
+	logger, _ := New(env, Options{})
+	zap.ReplaceGlobals(logger)
+	return logger
+}
+
+// WithContext tags log lines with the request ID and the trace and span
+// IDs carried by ctx so both services' logs can be joined with the spans.
+func WithContext(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	var fields []zap.Field
+
+	if id := requestid.FromContext(ctx); id != "" {
+		fields = append(fields, zap.String("request_id", id))
+	}
+
+	spanContext := trace.SpanContextFromContext(ctx)
+	if spanContext.IsValid() {
+		fields = append(fields,
+			zap.String("trace_id", spanContext.TraceID().String()),
+			zap.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+
+	if len(fields) == 0 {
+		return logger
+	}
+
+	return logger.With(fields...)
+}
@@ -0,0 +1,47 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+
+	maxLength = 128
+)
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+func Generate() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
+
+// IsValid rejects IDs that are empty, oversized or carry characters that
+// would break log lines or headers when echoed back.
+func IsValid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for _, char := range id {
+		if char < 0x21 || char > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,211 @@
+package tlsreload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Reloader keeps the certificate, key and optional CA bundle in memory and
+// polls the files for changes, so rotated certificates are used by the
+// next handshake without restarting the process.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mutex       sync.RWMutex
+	certificate *tls.Certificate
+	pool        *x509.CertPool
+	modTimes    [3]time.Time
+}
+
+func New(certFile, keyFile, caFile string) (*Reloader, error) {
+	reloader := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		caFile:   caFile,
+	}
+
+	if err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+
+	return reloader, nil
+}
+
+func (reloader *Reloader) Watch(ctx context.Context, interval time.Duration, log *zap.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := reloader.changed()
+		if err != nil {
+			log.Warn("Failed to stat TLS files", zap.Error(err))
+			continue
+		}
+
+		if !changed {
+			continue
+		}
+
+		if err := reloader.Reload(); err != nil {
+			log.Error("Failed to reload TLS certificates, keeping the previous ones", zap.Error(err))
+			continue
+		}
+
+		log.Info("TLS certificates reloaded", zap.String("cert", reloader.certFile))
+	}
+}
+
+// errNoCA stops mTLS from trusting the system roots when no CA bundle was
+// given; only GetCertificate works without one.
+var errNoCA = errors.New("mTLS needs a CA bundle, none is configured")
+
+// ServerConfig requires and verifies a client certificate signed by the
+// configured CA.
+func (reloader *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			reloader.mutex.RLock()
+			defer reloader.mutex.RUnlock()
+
+			if reloader.pool == nil {
+				return nil, errNoCA
+			}
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*reloader.certificate},
+				ClientCAs:    reloader.pool,
+				ClientAuth:   tls.RequireAndVerifyClientCert,
+			}, nil
+		},
+	}
+}
+
+// ClientConfig presents our certificate and verifies the server against
+// the current CA bundle. Verification is done by hand in VerifyConnection
+// because RootCAs can't be swapped after the config is built.
+func (reloader *Reloader) ClientConfig(serverName string) *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			reloader.mutex.RLock()
+			defer reloader.mutex.RUnlock()
+			return reloader.certificate, nil
+		},
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return errors.New("server presented no certificate")
+			}
+
+			reloader.mutex.RLock()
+			pool := reloader.pool
+			reloader.mutex.RUnlock()
+
+			if pool == nil {
+				return errNoCA
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, cert := range state.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := state.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       serverName,
+				Roots:         pool,
+				Intermediates: intermediates,
+			})
+			return err
+		},
+	}
+}
+
+// GetCertificate serves the current certificate to plain TLS servers that
+// don't verify clients.
+func (reloader *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	reloader.mutex.RLock()
+	defer reloader.mutex.RUnlock()
+	return reloader.certificate, nil
+}
+
+func (reloader *Reloader) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(reloader.certFile, reloader.keyFile)
+	if err != nil {
+		return err
+	}
+
+	var pool *x509.CertPool
+	if reloader.caFile != "" {
+		caBundle, err := os.ReadFile(reloader.caFile)
+		if err != nil {
+			return err
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return fmt.Errorf("no certificates found in %s", reloader.caFile)
+		}
+	}
+
+	modTimes, err := reloader.stat()
+	if err != nil {
+		return err
+	}
+
+	reloader.mutex.Lock()
+	defer reloader.mutex.Unlock()
+
+	reloader.certificate = &certificate
+	reloader.pool = pool
+	reloader.modTimes = modTimes
+
+	return nil
+}
+
+func (reloader *Reloader) changed() (bool, error) {
+	modTimes, err := reloader.stat()
+	if err != nil {
+		return false, err
+	}
+
+	reloader.mutex.RLock()
+	defer reloader.mutex.RUnlock()
+
+	return modTimes != reloader.modTimes, nil
+}
+
+func (reloader *Reloader) stat() ([3]time.Time, error) {
+	var modTimes [3]time.Time
+	for index, file := range []string{reloader.certFile, reloader.keyFile, reloader.caFile} {
+		if file == "" {
+			continue
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return modTimes, err
+		}
+		modTimes[index] = info.ModTime()
+	}
+
+	return modTimes, nil
+}
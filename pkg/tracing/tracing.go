@@ -0,0 +1,76 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+type ShutdownFunc func(ctx context.Context) error
+
+// Init installs the global tracer provider, with serviceName on its
+// resource, and the W3C trace context propagator. With the "none" exporter
+// spans are still created, so trace IDs keep showing up in logs and are
+// still propagated between the services.
+func Init(serviceName, exporter, otlpEndpoint string) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	// Schemaless, so the SDK's default resource decides the schema URL and
+	// a bump of either can't make the merge conflict at startup.
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	options := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+
+	spanExporter, err := newExporter(exporter, otlpEndpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if spanExporter != nil {
+		options = append(options, sdktrace.WithBatcher(spanExporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(options...)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(exporter, otlpEndpoint string) (sdktrace.SpanExporter, error) {
+	switch exporter {
+	case ExporterNone, "":
+		return nil, nil
+	case ExporterStdout:
+		return stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	case ExporterOTLP:
+		return otlptracegrpc.New(
+			context.Background(),
+			otlptracegrpc.WithEndpoint(otlpEndpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", exporter)
+	}
+}